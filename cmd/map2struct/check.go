@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const generatePrefix = "//go:generate "
+
+// generateLine 包中调用 map2struct 的 //go:generate 指令
+type generateLine struct {
+	file string // 指令所在的文件名
+	pkg  string // 包名
+	line int
+	args []string // 去掉命令之后的参数，已经按 go generate 的规则展开环境变量
+}
+
+// findGenerateLines 查找 dir 中命令为 map2struct 的 //go:generate 指令，按文件名和行号排序
+func findGenerateLines(dir string) ([]*generateLine, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var list []*generateLine
+	for _, info := range infos {
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".go") {
+			continue
+		}
+		lines, err := fileGenerateLines(filepath.Join(dir, info.Name()))
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, lines...)
+	}
+	return list, nil
+}
+
+func fileGenerateLines(path string) ([]*generateLine, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		list    []*generateLine
+		pkgName string
+	)
+	scanner := bufio.NewScanner(f)
+	for num := 1; scanner.Scan(); num++ {
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		if pkgName == "" && strings.HasPrefix(text, "package ") {
+			pkgName = strings.TrimSpace(strings.TrimPrefix(text, "package "))
+		}
+		if !strings.HasPrefix(text, generatePrefix) {
+			continue
+		}
+		words, err := splitGenerateArgs(text[len(generatePrefix):])
+		if err != nil {
+			return nil, fmt.Errorf("invalid go:generate. file=%s line=%d err=%v", path, num, err)
+		}
+		if len(words) == 0 || filepath.Base(words[0]) != "map2struct" {
+			continue
+		}
+		list = append(list, &generateLine{file: filepath.Base(path), line: num, args: words[1:]})
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	for _, gl := range list {
+		gl.pkg = pkgName
+		for idx, arg := range gl.args {
+			gl.args[idx] = os.Expand(arg, gl.expandVar)
+		}
+	}
+	return list, nil
+}
+
+// expandVar 与 go generate 相同的环境变量
+func (gl *generateLine) expandVar(name string) string {
+	switch name {
+	case "GOFILE":
+		return gl.file
+	case "GOPACKAGE":
+		return gl.pkg
+	case "GOLINE":
+		return strconv.Itoa(gl.line)
+	case "DOLLAR":
+		return "$"
+	}
+	return os.Getenv(name)
+}
+
+// splitGenerateArgs 按 go generate 的规则分割参数：空格分隔，双引号包含的字符串按 Go 语法解析
+func splitGenerateArgs(line string) ([]string, error) {
+	var words []string
+	for line = strings.TrimLeft(line, " \t"); line != ""; line = strings.TrimLeft(line, " \t") {
+		if line[0] != '"' {
+			end := strings.IndexAny(line, " \t")
+			if end < 0 {
+				end = len(line)
+			}
+			words = append(words, line[:end])
+			line = line[end:]
+			continue
+		}
+		end := 1
+		for ; end < len(line) && line[end] != '"'; end++ {
+			if line[end] == '\\' {
+				end++
+			}
+		}
+		if end >= len(line) {
+			return nil, fmt.Errorf("unterminated quoted string. line=%s", line)
+		}
+		word, err := strconv.Unquote(line[:end+1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid quoted string. word=%s err=%v", line[:end+1], err)
+		}
+		words = append(words, word)
+		line = line[end+1:]
+	}
+	return words, nil
+}
+
+// checkGenerateLines -check 的包模式: 使用每个 //go:generate 指令的参数在包目录中检查，与 go generate 的结果一致
+func checkGenerateLines(dir string, lines []*generateLine) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	for _, gl := range lines {
+		pos := fmt.Sprintf("%s:%d", relPath(filepath.Join(dir, gl.file)), gl.line)
+		log.Printf("check: %s map2struct %s", pos, strings.Join(gl.args, " "))
+		cmd := exec.Command(exe, append([]string{"-check"}, gl.args...)...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GOFILE="+gl.file, "GOPACKAGE="+gl.pkg, "GOLINE="+strconv.Itoa(gl.line))
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err = cmd.Run(); err != nil {
+			if _, ok := err.(*exec.ExitError); !ok {
+				return err
+			}
+			// 生成的文件不是最新的，或者生成失败
+			staleFiles = append(staleFiles, pos)
+		}
+	}
+	return nil
+}
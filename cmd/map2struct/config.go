@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 项目配置文件，从输入文件所在目录向上查找，直到 go.mod 所在的目录
+var configNames = []string{".map2struct.yaml", ".map2struct.yml", ".map2struct.json"}
+
+// 配置文件中可以使用的参数，key 与命令行参数相同，比如: strict: true
+var configFlags = []string{
+	"tag", "unknown", "strict", "presence", "test", "slice", "csv", "register",
+	"prefix", "match", "name", "exported", "onefile", "suffix", "maxindex",
+}
+
+var (
+	configFile string          // 使用的配置文件
+	configured map[string]bool // 配置文件中设置的参数
+)
+
+// findConfig 从 dir 向上查找配置文件，不存在返回空
+func findConfig(dir string) string {
+	for {
+		for _, name := range configNames {
+			if file := filepath.Join(dir, name); isFile(file) {
+				return file
+			}
+		}
+		parent := filepath.Dir(dir)
+		if isFile(filepath.Join(dir, "go.mod")) || parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func isFile(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && !fi.IsDir()
+}
+
+// loadConfig 读取 dir 对应的配置文件，命令行没有指定的参数使用配置文件中的值；先恢复上一个配置文件设置的参数
+func loadConfig(dir string) error {
+	for name := range configured {
+		f := flag.Lookup(name)
+		if err := f.Value.Set(f.DefValue); err != nil {
+			return fmt.Errorf("reset config failed. key=%s err=%v", name, err)
+		}
+	}
+	configured = map[string]bool{}
+	if configFile = findConfig(dir); configFile == "" {
+		return nil
+	}
+	data, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		return fmt.Errorf("read config failed. file=%s err=%v", configFile, err)
+	}
+
+	values := map[string]interface{}{}
+	if filepath.Ext(configFile) == ".json" {
+		err = json.Unmarshal(data, &values)
+	} else {
+		err = yaml.Unmarshal(data, &values)
+	}
+	if err != nil {
+		return fmt.Errorf("parse config failed. file=%s err=%v", configFile, err)
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := values[name]
+		if !isConfigFlag(name) {
+			return fmt.Errorf("unknown config. file=%s key=%s", configFile, name)
+		}
+		if isFlagSet(name) {
+			continue
+		}
+		// 直接修改参数的值，不影响 isFlagSet，函数指令仍然优先
+		if err = flag.Lookup(name).Value.Set(fmt.Sprint(value)); err != nil {
+			return fmt.Errorf("invalid config. file=%s key=%s value=%v err=%v", configFile, name, value, err)
+		}
+		configured[name] = true
+	}
+	return nil
+}
+
+// applyConfig 加载 dir 对应的配置文件并检查参数，包模式下每个包重新加载
+func applyConfig(dir string) error {
+	if err := loadConfig(dir); err != nil {
+		return err
+	}
+	switch *unknown {
+	case "", "ignore", "return", "callback", "error":
+	default:
+		return fmt.Errorf("invalid param. unknown=%s", *unknown)
+	}
+	if !strings.HasSuffix(*genSuffix, ".go") || strings.HasSuffix(*genSuffix, "_test.go") {
+		return fmt.Errorf("invalid param. suffix=%s", *genSuffix)
+	}
+	if *maxIndex <= 0 || *maxIndex > math.MaxInt32 {
+		return fmt.Errorf("invalid param. maxindex=%d", *maxIndex)
+	}
+	return initNaming(*funcMatch, *funcName)
+}
+
+func isConfigFlag(name string) bool {
+	for _, key := range configFlags {
+		if key == name {
+			return true
+		}
+	}
+	return false
+}
+
+// printConfig map2struct config print: 输出生效的参数及来源
+func printConfig(w io.Writer) error {
+	if configFile != "" {
+		fmt.Fprintf(w, "config: %s\n", configFile)
+	} else {
+		fmt.Fprintf(w, "config: none\n")
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, name := range configFlags {
+		source := "default"
+		switch {
+		case isFlagSet(name):
+			source = "flag"
+		case configured[name]:
+			source = "config"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t(%s)\n", name, flag.Lookup(name).Value, source)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"go/ast"
+	"io"
+	"log"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/adyzng/gotool/parse"
+	"github.com/adyzng/gotool/tpl"
+	"github.com/adyzng/gotool/utils"
+)
+
+// 环境变量加载函数: func LoadXxxFromEnv(prefix string) (*model.Xxx, error)
+const (
+	envPrefix = "Load"
+	envSuffix = "FromEnv"
+)
+
+// findEnvFuncList 查找环境变量加载函数，入参不是 map
+func findEnvFuncList(pkg *parse.PackageV2) ([]*parse.FunctionV2, error) {
+	fnList, err := pkg.FindFuncList(envPrefix)
+	if err != nil {
+		return nil, err
+	}
+	var list []*parse.FunctionV2
+	for _, fun := range fnList {
+		if strings.HasSuffix(fun.Name, envSuffix) && fun.InputParam == nil && fun.OutputParam != nil && !strings.HasSuffix(fun.File, *genSuffix) {
+			list = append(list, fun)
+		}
+	}
+	sortFuncs(list)
+	return list, nil
+}
+
+func loadFromEnv(pkg *parse.PackageV2, fun *parse.FunctionV2, writer io.Writer) (err error) {
+	baseName := strings.TrimSuffix(strings.TrimPrefix(fun.Name, envPrefix), envSuffix)
+	ctorData := tpl.MapToStructTemplateData{
+		ModelPkg:  fun.OutputType.Package,
+		ModelName: fun.OutputType.TypeName,
+	}
+	if err = setConstructor(fun, &ctorData); err != nil {
+		log.Printf("process constructor failed. func=%s err=%v", fun.Name, err)
+		return err
+	}
+
+	tplData := tpl.EnvTemplateData{
+		FuncName:   genName(fun, baseName, ""),
+		VarsName:   genName(fun, baseName, "%sEnvVars"),
+		ModelPkg:   ctorData.ModelPkg,
+		ModelName:  ctorData.ModelName,
+		NewExpr:    ctorData.NewExpr,
+		InitMethod: ctorData.InitMethod,
+	}
+	if err = claimName(tplData.FuncName, fun); err != nil {
+		return err
+	}
+	if err = envFields(pkg, fun.OutputParam, "", "", &tplData); err != nil {
+		log.Printf("process fields failed. func=%s err=%v", fun.Name, err)
+		return err
+	}
+
+	tplInst := template.New("loadFromEnv")
+	if tplInst, err = tplInst.Parse(tpl.EnvTemplate); err != nil {
+		log.Printf("template parsed failed. err=%v", err)
+		return err
+	}
+	if err = tplInst.Execute(writer, &tplData); err != nil {
+		log.Printf("template exceute failed. err=%v", err)
+		return err
+	}
+	return nil
+}
+
+// envFields 遍历结构体字段，嵌套结构体的字段名和变量名分别加上 path 和 envPath 前缀
+func envFields(pkg *parse.PackageV2, st *parse.StructV2, path, envPath string, data *tpl.EnvTemplateData) (err error) {
+	input := &parse.MapType{KeyType: "string", ValueType: "string"}
+	st.EnumField(func(fd *ast.Field) bool {
+		ft := st.FieldType(fd)
+		if !ft.IsExported() && st.Package != pkg {
+			return true
+		}
+		name, def, required, allowEmpty := envTag(ft)
+		if name == "" {
+			return true
+		}
+		fieldName := path + ft.Name
+		envName := envPath + name
+
+		// 嵌套结构体: APP_DB_HOST
+		if nested := nestedStruct(st, ft); nested != nil {
+			if ft.Kind == parse.Pointer {
+				alloc := &tpl.EnvAlloc{FieldName: fieldName}
+				alloc.NewExpr, alloc.InitMethod = structAlloc(nested, ft.Package)
+				data.Allocs = append(data.Allocs, alloc)
+			}
+			if err = envFields(pkg, nested, fieldName+".", envName+"_", data); err != nil {
+				return false
+			}
+			return true
+		}
+
+		fdItem := &tpl.FieldItem{
+			JsonName:  envName,
+			FieldName: fieldName,
+			FieldType: ft.Type,
+			IsPointer: ft.Kind == parse.Pointer,
+			TypeEqual: ft.Type == input.ValueType,
+		}
+		item := &tpl.EnvFieldItem{FieldItem: fdItem, Name: envName, Type: ft.Type, Default: def, Required: required, AllowEmpty: allowEmpty}
+		if ft.IsObjectType() {
+			item.Type = ft.Package + "." + ft.Type
+		}
+
+		if err = classifyField(pkg, ft, input, fdItem); err != nil {
+			return false
+		}
+		switch fdItem.GenType {
+		case "direct", "assign", "enum":
+			item.Index = len(data.Fields)
+			data.Fields = append(data.Fields, item)
+		default:
+			data.OtherFields = append(data.OtherFields, item)
+			log.Printf("⚠️ unknown field. field=%s.%s type=%+v", st.Name, ft.Name, ft)
+		}
+		return true
+	})
+	return err
+}
+
+// envTag 解析 env:"NAME,required,allowempty,default=xxx" tag，default 必须放在最后，值可以包含逗号；
+// 没有 tag 时使用字段名的 SCREAMING_SNAKE 形式，tag 为 "-" 时返回空
+func envTag(ft *parse.TypeInfo) (name, def string, required, allowEmpty bool) {
+	tag := reflect.StructTag(ft.Tag).Get("env")
+	if idx := strings.Index(tag, "default="); idx >= 0 {
+		def = tag[idx+len("default="):]
+		tag = strings.TrimSuffix(tag[:idx], ",")
+	}
+	opts := strings.Split(tag, ",")
+	name = opts[0]
+	required = utils.InStrings(opts[1:], "required")
+	allowEmpty = utils.InStrings(opts[1:], "allowempty")
+	switch name {
+	case "-":
+		return "", "", false, false
+	case "":
+		name = strings.ToUpper(utils.ToSnakeCase(ft.Name))
+	}
+	return name, def, required, allowEmpty
+}
+
+// nestedStruct 字段类型为结构体时返回结构体定义
+func nestedStruct(st *parse.StructV2, ft *parse.TypeInfo) *parse.StructV2 {
+	if !ft.IsObjectType() || ft.Kind == parse.Array || ft.Package == "time" {
+		return nil
+	}
+	depPkg, err := st.Package.GetImportPkg(ft.Package)
+	if err != nil || depPkg == nil {
+		return nil
+	}
+	if ti := depPkg.GetTypeIdent(ft.Type); ti.Kind != parse.Struct {
+		return nil
+	}
+	nested, err := depPkg.FindStruct(ft.Type)
+	if err != nil {
+		return nil
+	}
+	return nested
+}
@@ -8,8 +8,11 @@ import (
 	"io"
 	"io/ioutil"
 	"log"
+	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -20,14 +23,40 @@ import (
 	"github.com/adyzng/gotool/utils"
 )
 
+const (
+	m2sTag = "m2s" // 生成器使用的 tag，比如: m2s:"author,prefix=author."
+)
+
 var (
-	input  = flag.String("input", "", "input file path")
-	output = flag.String("output", "", "output file path; default ./<input>_gen.go")
+	input    = flag.String("input", "", "input file path")
+	output   = flag.String("output", "", "output file path; default ./<input>_gen.go")
+	tagName  = flag.String("tag", "json", "struct tag used as map key, fallback to json tag and field name")
+	unknown  = flag.String("unknown", "", "report source keys not in struct: return|callback|error; default ignore")
+	genTest  = flag.Bool("test", false, "also generate <input>_gen_test.go with unit tests, benchmarks and fuzz targets")
+	presence = flag.Bool("presence", false, "also return the set of fields present in source map")
+	strict   = flag.Bool("strict", false, "return error when value conversion failed or lost precision")
+	genSlice = flag.Bool("slice", false, "also generate batch converters genMapToXxxSlice and genMapToXxxSliceParallel")
+	genCSV   = flag.Bool("csv", false, "also generate streaming CSV reader and writer for map[string]string functions")
+	register = flag.Bool("register", false, "register converters to m2s at init, used by m2s.DecodeInto/DecodeNew")
+	typeList = flag.String("type", "", "comma-separated struct names to generate genMapTo<Type> without stub functions")
+	typePkg  = flag.String("pkg", "", "import path of the package where -type structs are defined")
+	typeSrc  = flag.String("src", "string", "source map value type for -type: string|interface")
+
+	funcPrefix = flag.String("prefix", "MapTo", "name prefix of stub functions to generate")
+	funcMatch  = flag.String("match", "", "regexp of stub function names, overrides -prefix; first group is the base name")
+	funcName   = flag.String("name", "gen{{.Name}}", "template of generated names with .Name, .Base and .Model")
+	exported   = flag.Bool("exported", false, "export generated functions and types")
+	oneFile    = flag.Bool("onefile", false, "with packages, write one <package>_gen.go per package instead of one per input file")
+	genSuffix  = flag.String("suffix", "_gen.go", "file name suffix of generated files")
+	check      = flag.Bool("check", false, "do not write files, exit non-zero with a diff if generated files are out of date")
+	maxIndex   = flag.Int("maxindex", 1000, "upper bound (exclusive) of indexes in flattened slice keys like items.0.title")
 )
 
 func Usage() {
 	fmt.Fprintf(os.Stderr, "Usage of map2struct:\n")
 	fmt.Fprintf(os.Stderr, "    map2struct [flags] -input=xx -output=xx\n")
+	fmt.Fprintf(os.Stderr, "    map2struct [flags] ./... | packages\n")
+	fmt.Fprintf(os.Stderr, "    map2struct [flags] config print\n")
 	fmt.Fprintf(os.Stderr, "For more information, see:\n")
 	fmt.Fprintf(os.Stderr, "    https://github.com/adyzng/gotool/blob/master/cmd/map2struct/README.md\n")
 	fmt.Fprintf(os.Stderr, "Flags:\n")
@@ -40,6 +69,35 @@ func main() {
 	flag.Usage = Usage
 	flag.Parse()
 
+	if err := applyConfig(configDir()); err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	if flag.Arg(0) == "config" {
+		if flag.NArg() != 2 || flag.Arg(1) != "print" {
+			log.Fatalf("unknown command. args=%v", flag.Args())
+			return
+		}
+		if err := printConfig(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if flag.NArg() > 0 {
+		if err := generatePackages(flag.Args()); err != nil {
+			log.Fatal(err)
+			return
+		}
+		if len(staleFiles) > 0 {
+			log.Fatalf("generated files are out of date. files=%s", strings.Join(staleFiles, ","))
+			return
+		}
+		log.Printf("succeed")
+		return
+	}
+
 	inputFile := *input
 	outputFile := *output
 
@@ -76,132 +134,940 @@ func main() {
 		return
 	}
 
-	fnList, err := genPkg.FindFuncList("MapTo")
-	if err != nil {
+	out := &genOutput{file: outputFile}
+	if out.funcs, err = findFuncList(genPkg); err != nil {
 		log.Fatalf("parse function failed. path=%s err=%v", inputFile, err)
 		return
 	}
 
-	buffer := bytes.NewBuffer(make([]byte, 0, 4096))
-	if err = processPrefix(genPkg, buffer); err != nil {
-		log.Fatalf("process package failed. err=%v", err)
+	typeFuncs, typeImports, err := findTypeFuncList(genPkg, *typeList, *typePkg, *typeSrc)
+	if err != nil {
+		log.Fatalf("parse type failed. type=%s err=%v", *typeList, err)
+		return
+	}
+	for _, fun := range typeFuncs {
+		for _, prev := range out.funcs {
+			if prev.Name == fun.Name {
+				log.Fatalf("function already declared. func=%s", fun.Name)
+				return
+			}
+		}
+		out.funcs = append(out.funcs, fun)
+	}
+	out.imports = typeImports
+
+	if err = generate(genPkg, out); err != nil {
+		log.Fatalf("generate failed. path=%s err=%v", inputFile, err)
+		return
+	}
+
+	if len(staleFiles) > 0 {
+		log.Fatalf("generated files are out of date. files=%s", strings.Join(staleFiles, ","))
 		return
 	}
+	log.Printf("succeed")
+	return
+}
+
+// genOutput 写入同一个文件的函数
+type genOutput struct {
+	file    string              // 输出文件路径
+	funcs   []*parse.FunctionV2 // map 转换函数，包括 map2struct、环境变量、数据库扫描
+	imports []string            // 额外 import 的包
+}
 
+// findFuncList 查找包中所有需要生成的函数
+func findFuncList(pkg *parse.PackageV2) ([]*parse.FunctionV2, error) {
+	fnList, err := findStubList(pkg)
+	if err != nil {
+		return nil, err
+	}
+	var list []*parse.FunctionV2
 	for _, fun := range fnList {
-		log.Printf(
-			"➡️ func=%s ing, in=map[%s]%s, out=%s.%s",
-			fun.Name, fun.InputParam.KeyType, fun.InputParam.ValueType,
-			fun.OutputType.Package, fun.OutputType.TypeName,
-		)
-		//if fun.OutputParam == nil {
-		//	log.Printf("function first return value is not struct. func=%v", fun)
-		//	continue
-		//}
-		if err = map2Struct(genPkg, fun, buffer); err != nil {
-			log.Printf("process function failed. func=%v err=%v", fun, err)
-			return
+		list = append(list, fun)
+	}
+	sortFuncs(list)
+
+	envList, err := findEnvFuncList(pkg)
+	if err != nil {
+		return nil, err
+	}
+	scanList, err := findScanFuncList(pkg)
+	if err != nil {
+		return nil, err
+	}
+	list = append(list, envList...)
+	return append(list, scanList...), nil
+}
+
+// sortFuncs 按函数名排序，每次生成的结果相同，-register 时同一对类型也总是注册同一个函数
+func sortFuncs(list []*parse.FunctionV2) {
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+}
+
+// generate 生成 out 中的函数并写入文件，-test 时同时写入单测文件
+func generate(pkg *parse.PackageV2, out *genOutput) (err error) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 4096))
+	if err = processPrefix(pkg, tpl.MapToStructPrefix, out.imports, buffer); err != nil {
+		return err
+	}
+
+	var testBuffer *bytes.Buffer
+	var testWriter io.Writer // 不生成单测时必须为 nil 接口
+	if *genTest {
+		testBuffer = bytes.NewBuffer(make([]byte, 0, 4096))
+		if err = processPrefix(pkg, tpl.MapToStructTestPrefix, out.imports, testBuffer); err != nil {
+			return err
+		}
+		testWriter = testBuffer
+	}
+
+	for _, fun := range out.funcs {
+		switch {
+		case fun.InputParam != nil:
+			log.Printf(
+				"➡️ func=%s ing, in=%s, out=%s.%s",
+				fun.Name, fun.InputParam,
+				fun.OutputType.Package, fun.OutputType.TypeName,
+			)
+			err = map2Struct(pkg, fun, buffer, testWriter)
+		case scanParam(fun) != "":
+			log.Printf("➡️ func=%s ing, in=sql.%s, out=%s.%s", fun.Name, scanParam(fun), fun.OutputType.Package, fun.OutputType.TypeName)
+			err = scanRows(pkg, fun, buffer)
+		default:
+			log.Printf("➡️ func=%s ing, in=env, out=%s.%s", fun.Name, fun.OutputType.Package, fun.OutputType.TypeName)
+			err = loadFromEnv(pkg, fun, buffer)
+		}
+		if err != nil {
+			return fmt.Errorf("process function failed. func=%s err=%v", fun.Name, err)
 		}
 		log.Printf("✅ func=%s done", fun.Name)
 	}
 
 	// log.Printf("%s", buffer.String())
-	if err = saveOutput(buffer.Bytes(), outputFile); err != nil {
+	if err = saveOutput(buffer.Bytes(), out.file); err != nil {
 		log.Printf("%s", buffer.Bytes())
-		log.Fatalf("save output failed. err=%v", err)
-		return
+		return fmt.Errorf("save output failed. err=%v", err)
 	}
+	log.Printf("output: %s", out.file)
 
-	log.Printf("succeed")
-	return
+	if testBuffer != nil {
+		testFile := strings.TrimSuffix(out.file, ".go") + "_test.go"
+		if err = saveOutput(testBuffer.Bytes(), testFile); err != nil {
+			log.Printf("%s", testBuffer.Bytes())
+			return fmt.Errorf("save test output failed. err=%v", err)
+		}
+		log.Printf("test output: %s", testFile)
+	}
+	return nil
 }
 
-func map2Struct(pkg *parse.PackageV2, fun *parse.FunctionV2, writer io.Writer) (err error) {
-	tplData := tpl.MapToStructTemplateData{
-		FuncName:     "gen" + fun.Name,
-		ParamName:    "src",
-		ParamType:    fmt.Sprintf("map[%s]%s", fun.InputParam.KeyType, fun.InputParam.ValueType),
+func map2Struct(pkg *parse.PackageV2, fun *parse.FunctionV2, writer io.Writer, testWriter io.Writer) (err error) {
+	if len(fun.InputParams) > 1 {
+		return mapToStructMerge(pkg, fun, writer)
+	}
+
+	baseName := stubBase(fun.Name)
+	tplData, err := newTemplateData(pkg, fun, fun.InputParam, "src", genName(fun, baseName, "Apply%s"))
+	if err != nil {
+		return err
+	}
+	if err = claimName(tplData.FuncName, fun); err != nil {
+		return err
+	}
+	if *register {
+		setRegister(fun, tplData)
+	}
+
+	tplInst := template.New("mapToStruct")
+	if tplInst, err = tplInst.Parse(tpl.MapToStructTemplate); err != nil {
+		log.Printf("template parsed failed. err=%v", err)
+		return err
+	}
+	if err = executeTemplate(tplInst, writer, tplData); err != nil {
+		return err
+	}
+
+	if *genCSV {
+		if err = mapToCSV(fun, tplData, writer); err != nil {
+			return err
+		}
+	}
+
+	if testWriter != nil {
+		testInst := template.New("mapToStructTest")
+		if testInst, err = testInst.Parse(tpl.MapToStructTestTemplate); err != nil {
+			log.Printf("template parsed failed. err=%v", err)
+			return err
+		}
+		if err := testInst.Execute(testWriter, tplData); err != nil {
+			log.Printf("template exceute failed. err=%v", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// executeTemplate 生成转换函数，以及带下标的切片中结构体元素的 Apply 函数
+func executeTemplate(tplInst *template.Template, writer io.Writer, data *tpl.MapToStructTemplateData) error {
+	if err := tplInst.Execute(writer, data); err != nil {
+		log.Printf("template exceute failed. err=%v", err)
+		return err
+	}
+	for _, elem := range data.Elems {
+		if err := executeTemplate(tplInst, writer, elem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newTemplateData 分析结构体字段，生成从 input 转换的模板数据
+func newTemplateData(pkg *parse.PackageV2, fun *parse.FunctionV2, input *parse.MapType, paramName, applyName string) (*tpl.MapToStructTemplateData, error) {
+	baseName := stubBase(fun.Name)
+	funcName := genName(fun, baseName, "")
+	tplData := &tpl.MapToStructTemplateData{
+		FuncName:     funcName,
+		ApplyName:    applyName,
+		BaseName:     baseName,
+		ParamName:    paramName,
+		ParamType:    input.String(),
+		ValueType:    input.ValueType,
+		TestName:     utils.ToCap(funcName),
+		TestSrcName:  "test" + utils.ToCap(genName(fun, baseName, "%sSrc")),
 		ModelName:    fun.OutputType.TypeName,
 		ModelPkg:     fun.OutputType.Package,
+		Strict:       *strict,
+		MaxIndex:     *maxIndex,
+		Slice:        *genSlice,
+		Presence:     *presence,
+		FieldEnum:    genName(fun, baseName, "%sField"),
+		FieldsType:   genName(fun, baseName, "%sFields"),
 		EnumFields:   []*tpl.FieldItem{},
 		DirectFields: []*tpl.FieldItem{}, // 类型相同
 		AssignFields: []*tpl.FieldItem{}, // optional 的字段，
 		OtherFields:  []*tpl.FieldItem{}, // 类型不同的，非optional字段
 	}
 
-	input := fun.InputParam
-	model := fun.OutputParam
-	model.EnumField(func(fd *ast.Field) bool {
-		ft := model.FieldType(fd)
+	if input.Multi {
+		tplData.ValueType = "[]" + input.ValueType
+	}
+	if err := setConstructor(fun, tplData); err != nil {
+		log.Printf("process constructor failed. func=%s err=%v", fun.Name, err)
+		return nil, err
+	}
+
+	keys, err := newFieldKeys(fun)
+	if err != nil {
+		return nil, err
+	}
+	scope := &fieldScope{
+		prefix: fun.Directive("prefix"),
+		keys:   keys,
+		types:  []string{fun.OutputType.Package + "." + fun.OutputType.TypeName},
+	}
+	if err = structFields(pkg, fun, fun.OutputParam, input, tplData, scope); err != nil {
+		return nil, err
+	}
+	if names := keys.unused(); len(names) > 0 {
+		return nil, fmt.Errorf("field in directive not found. func=%s fields=%s", fun.Name, strings.Join(names, ","))
+	}
+
+	tplData.FieldWords = fieldWords(len(tplData.Fields))
+	setExtraSignature(tplData)
+	return tplData, nil
+}
+
+// fieldScope 嵌套结构体和切片元素的字段所在的位置
+type fieldScope struct {
+	path    string     // 字段路径，比如: "Author."
+	name    string     // 指令中使用的字段路径，切片元素为 "Chapters."
+	prefix  string     // key 的前缀，比如: "author."
+	dynamic bool       // key 前缀还包括 Apply 函数的 prefix 参数（切片元素）
+	alloc   string     // 赋值前分配为 nil 的父结构体
+	keys    *fieldKeys // 函数指令中的字段配置
+	types   []string   // 路径上的结构体类型，防止循环嵌套
+}
+
+// fieldKeys 函数指令中的字段配置: //map2struct:field Name=title Id=book_id, //map2struct:ignore CopyrightInfo
+type fieldKeys struct {
+	keys    map[string]string
+	ignored map[string]bool
+	used    map[string]bool
+}
+
+func newFieldKeys(fun *parse.FunctionV2) (*fieldKeys, error) {
+	fk := &fieldKeys{keys: map[string]string{}, ignored: map[string]bool{}, used: map[string]bool{}}
+	for _, dir := range fun.Directives {
+		switch dir.Name {
+		case "field":
+			for _, pair := range strings.Fields(dir.Args) {
+				ss := strings.SplitN(pair, "=", 2)
+				if len(ss) != 2 || ss[0] == "" || ss[1] == "" {
+					return nil, fmt.Errorf("invalid field directive, want Name=key. func=%s args=%s", fun.Name, dir.Args)
+				}
+				fk.keys[ss[0]] = ss[1]
+			}
+		case "ignore":
+			for _, name := range strings.FieldsFunc(dir.Args, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+				fk.ignored[name] = true
+			}
+		case "new", "flatten", "tag", "prefix":
+		default:
+			log.Printf("⚠️ unknown directive. func=%s directive=%s", fun.Name, dir.Name)
+		}
+	}
+	return fk, nil
+}
+
+// key 指令中指定的 key，ignore 或者 Name=- 时 ignored 为 true
+func (fk *fieldKeys) key(name string) (key string, ignored bool) {
+	if fk.ignored[name] {
+		fk.used[name] = true
+		return "", true
+	}
+	if key, ok := fk.keys[name]; ok {
+		fk.used[name] = true
+		return key, key == "-"
+	}
+	return "", false
+}
+
+// unused 没有匹配到字段的指令，可能是拼写错误或者模型已经修改
+func (fk *fieldKeys) unused() []string {
+	var names []string
+	for name := range fk.keys {
+		if !fk.used[name] {
+			names = append(names, name)
+		}
+	}
+	for name := range fk.ignored {
+		if !fk.used[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// keyExpr key 的表达式
+func (sc *fieldScope) keyExpr(key string) string {
+	if sc.dynamic {
+		return "prefix + " + strconv.Quote(sc.prefix+key)
+	}
+	return strconv.Quote(sc.prefix + key)
+}
+
+// structFields 分析结构体的字段，嵌套结构体的字段展开到 data 中
+func structFields(pkg *parse.PackageV2, fun *parse.FunctionV2, st *parse.StructV2, input *parse.MapType, data *tpl.MapToStructTemplateData, scope *fieldScope) (err error) {
+	keyTags := sourceTags(fun, input)
+	isProto, isThrift := st.IsProto(), st.IsThrift()
+	addField := func(ft *parse.TypeInfo, fdItem *tpl.FieldItem) {
+		if !utils.InStrings(data.KnownKeys, fdItem.JsonName) && fdItem.GenType != "indexed" {
+			data.KnownKeys = append(data.KnownKeys, fdItem.JsonName)
+		}
+		if fdItem.GenType != "" {
+			fdItem.FieldConst = data.FieldEnum + "_" + strings.ReplaceAll(scope.path+ft.Name, ".", "_")
+			data.Fields = append(data.Fields, fdItem)
+		}
+		if fdItem.KeyExpr == "" {
+			fdItem.KeyExpr = strconv.Quote(fdItem.JsonName)
+		}
+		fdItem.Lookup = lookupExpr(data.ParamName, fdItem, input)
+		fdItem.Alloc = scope.alloc
+
+		switch fdItem.GenType {
+		case "enum":
+			data.EnumFields = append(data.EnumFields, fdItem)
+			log.Printf("enum field. field=%s.%s type=%+v", st.Name, ft.Name, ft)
+		case "direct":
+			data.DirectFields = append(data.DirectFields, fdItem)
+			log.Printf("direct field. field=%s.%s type=%+v", st.Name, ft.Name, ft)
+		case "assign":
+			data.AssignFields = append(data.AssignFields, fdItem)
+			log.Printf("assign field. field=%s.%s type=%+v", st.Name, ft.Name, ft)
+		case "oneof":
+			data.OneofFields = append(data.OneofFields, fdItem)
+			log.Printf("oneof field. field=%s.%s type=%+v", st.Name, ft.Name, ft)
+		case "slice":
+			data.SliceFields = append(data.SliceFields, fdItem)
+			log.Printf("slice field. field=%s.%s type=%+v", st.Name, ft.Name, ft)
+		case "indexed":
+			data.IndexedFields = append(data.IndexedFields, fdItem)
+			log.Printf("indexed field. field=%s.%s type=%+v", st.Name, ft.Name, ft)
+		case "json":
+			data.JSONFields = append(data.JSONFields, fdItem)
+			log.Printf("json field. field=%s.%s type=%+v", st.Name, ft.Name, ft)
+		default:
+			data.OtherFields = append(data.OtherFields, fdItem)
+			log.Printf("⚠️ unknown field. field=%s.%s type=%+v", st.Name, ft.Name, ft)
+		}
+	}
+
+	st.EnumField(func(fd *ast.Field) bool {
+		ft := st.FieldType(fd)
+		// 其他包中结构体的非导出字段无法赋值, proto 的 state/sizeCache/unknownFields, XXX_ 等内部字段
+		if (!ft.IsExported() && st.Package != pkg) || strings.HasPrefix(ft.Name, "XXX_") {
+			return true
+		}
+		dirKey, ignored := scope.keys.key(scope.name + ft.Name)
+		if ignored {
+			return true
+		}
+
+		// proto oneof: 每个包装类型对应一个 key
+		if isProto && ft.OneofName() != "" {
+			var items []*tpl.FieldItem
+			if items, err = oneofFields(pkg, st.Package, ft, input); err != nil {
+				log.Printf("process field failed=%s.%s, err=%v", st.Name, ft.Name, err)
+				return false
+			}
+			for _, item := range items {
+				item.KeyExpr = scope.keyExpr(item.JsonName)
+				item.FieldName = scope.path + item.FieldName
+				item.JsonName = scope.prefix + item.JsonName
+				addField(&parse.TypeInfo{Name: item.WrapperField}, item)
+			}
+			return true
+		}
+
+		key := ft.KeyName(keyTag(ft, keyTags))
+		if isThrift && !input.Multi && !explicitTag(fun) {
+			if name := ft.TagName("thrift"); name != "" {
+				key = name
+			}
+		}
+		if name := ft.TagName(m2sTag); name != "" {
+			key = name
+			if name == "-" {
+				key = ""
+			}
+		}
+		if tag := funcTag(fun); isProto && (tag == "json" || tag == "protobuf") {
+			if name := ft.ProtoName(); name != "" {
+				key = name
+			}
+		}
+		if dirKey != "" {
+			key = dirKey
+		}
+		if input.IsHeader() {
+			key = http.CanonicalHeaderKey(key)
+		}
+		if key == "" {
+			return true
+		}
+
+		// 嵌套结构体和带下标的切片: author.name, items.0.title
+		if prefix, ok := flattenPrefix(fun, ft, key, input); ok {
+			var done bool
+			if done, err = flattenField(pkg, fun, st, ft, input, data, scope, prefix, addField); err != nil || done {
+				return err == nil
+			}
+		}
+
+		// thrift 的 required 字段不受 -strict 影响
+		if isThrift && ft.IsRequired() && !scope.dynamic {
+			data.RequiredKeys = append(data.RequiredKeys, scope.prefix+key)
+		}
 		fdItem := &tpl.FieldItem{
-			JsonName:  ft.JsonName,
-			FieldName: ft.Name,
+			JsonName:  scope.prefix + key,
+			FieldName: scope.path + ft.Name,
 			FieldType: ft.Type,
+			KeyExpr:   scope.keyExpr(key),
 			IsPointer: ft.Kind == parse.Pointer,
 			TypeEqual: ft.Type == input.ValueType,
 		}
+		if err = classifyField(pkg, ft, input, fdItem); err != nil {
+			log.Printf("process field failed=%s.%s, err=%v", st.Name, ft.Name, err)
+			return false
+		}
 
+		addField(ft, fdItem)
+		return true
+	})
+	return err
+}
+
+// flattenPrefix 嵌套结构体或切片字段对应的 key 前缀: m2s:"author,prefix=author." 或者 //map2struct:flatten [sep]，
+// flatten 指令使用 key 加分隔符作为前缀，不展开 json、sep 选项的字段；多值 map 中基本类型的切片只有指定了 prefix 时才使用下标
+func flattenPrefix(fun *parse.FunctionV2, ft *parse.TypeInfo, key string, input *parse.MapType) (string, bool) {
+	if prefix, ok := ft.TagOption(m2sTag, "prefix"); ok {
+		return prefix, true
+	}
+	if !fun.HasDirective("flatten") || isJSONField(ft) || sepOption(ft) != "" || (input.Multi && ft.Kind == parse.Array && utils.IsBaseType(ft.Type)) {
+		return "", false
+	}
+	return key + flattenSep(fun), true
+}
+
+// isJSONField 字段的值为 JSON 字符串: m2s:"tags,json"
+func isJSONField(ft *parse.TypeInfo) bool {
+	_, ok := ft.TagOption(m2sTag, "json")
+	return ok
+}
+
+// sepOption 切片字段的值为分隔符拼接的字符串: m2s:"ids,sep=|"
+func sepOption(ft *parse.TypeInfo) string {
+	sep, _ := ft.TagOption(m2sTag, "sep")
+	return sep
+}
+
+// flattenSep flatten 指令指定的分隔符，默认为 "."
+func flattenSep(fun *parse.FunctionV2) string {
+	if sep := fun.Directive("flatten"); sep != "" {
+		return sep
+	}
+	return "."
+}
+
+// flattenField 展开嵌套结构体的字段，或者生成带下标的切片字段；不是结构体或切片时 done 为 false
+func flattenField(pkg *parse.PackageV2, fun *parse.FunctionV2, st *parse.StructV2, ft *parse.TypeInfo, input *parse.MapType,
+	data *tpl.MapToStructTemplateData, scope *fieldScope, prefix string, addField func(*parse.TypeInfo, *tpl.FieldItem)) (done bool, err error) {
+	typeName := ft.Package + "." + ft.Type
+	if ft.Kind == parse.Array {
+		fdItem := &tpl.FieldItem{
+			GenType:    "indexed",
+			JsonName:   scope.prefix + prefix,
+			FieldName:  scope.path + ft.Name,
+			FieldType:  ft.Type,
+			KeyExpr:    scope.keyExpr(prefix),
+			ElemPrefix: scope.keyExpr(prefix),
+			ElemSep:    flattenSep(fun),
+			ElemType:   ft.Type,
+		}
+		if utils.IsBaseType(ft.Type) {
+			elem := &tpl.FieldItem{
+				FieldName: fdItem.FieldName + "[i]",
+				FieldType: ft.Type,
+				JsonName:  fdItem.JsonName,
+				KeyExpr:   "key",
+				TypeEqual: ft.Type == input.ValueType,
+			}
+			if err = classifyField(pkg, &parse.TypeInfo{Type: ft.Type}, input, elem); err != nil || elem.GenType != "direct" {
+				return false, err
+			}
+			elem.Lookup = lookupExpr(data.ParamName, elem, input)
+			fdItem.Elem = elem
+			addField(ft, fdItem)
+			return true, nil
+		}
+
+		nested := nestedStruct(st, &parse.TypeInfo{Package: ft.Package, Type: ft.Type})
+		if nested == nil || utils.InStrings(scope.types, typeName) {
+			return false, nil
+		}
+		fdItem.ElemType = typeName
+		fdItem.ElemRef = "&obj." + fdItem.FieldName + "[i]"
+		// 已有的元素保留，指针元素为 nil 时创建，追加的元素使用构造函数或 Default
+		newExpr, initMethod := structAlloc(nested, ft.Package)
+		field := "obj." + fdItem.FieldName
 		switch {
-		case ft.IsBaseType(): // 基本类型
-			if ft.Kind == parse.Pointer {
-				fdItem.GenType = "assign"
-			} else {
-				fdItem.GenType = "direct"
+		case ft.ElemPtr:
+			fdItem.ElemType = "*" + typeName
+			fdItem.ElemRef = field + "[i]"
+			fdItem.ElemInit = fmt.Sprintf("if %s[i] == nil {\n\t%s[i] = %s", field, field, newExpr)
+			if initMethod != "" {
+				fdItem.ElemInit += fmt.Sprintf("\n\t%s[i].%s()", field, initMethod)
 			}
-			if !fdItem.TypeEqual || input.IsValueInterface {
-				fdItem.AssignExpr = fmt.Sprintf("cast.To%s", utils.ToCap(ft.Type))
+			fdItem.ElemInit += "\n}"
+		case !strings.HasPrefix(newExpr, "&"):
+			fdItem.ElemNew = fmt.Sprintf("%s = append(%s, *%s)", field, field, newExpr)
+		case initMethod != "":
+			fdItem.ElemNew = fmt.Sprintf("%s = append(%s, %s{})", field, field, typeName)
+		}
+		if initMethod != "" && !ft.ElemPtr {
+			fdItem.ElemNew += fmt.Sprintf("\n%s[len(%s)-1].%s()", field, field, initMethod)
+		}
+
+		// 元素的字段生成单独的 Apply 函数，key 的前缀通过参数传入
+		fdItem.ElemApply = data.ApplyName + strings.ReplaceAll(fdItem.FieldName, ".", "") + "Elem"
+		fdItem.ElemKey = fdItem.ElemApply + "Key"
+		elemData := &tpl.MapToStructTemplateData{
+			ApplyName:   fdItem.ElemApply,
+			ElemKeyFunc: fdItem.ElemKey,
+			BaseName:    data.BaseName,
+			ParamName:   data.ParamName,
+			ParamType:   data.ParamType,
+			ValueType:   data.ValueType,
+			ModelName:   nested.Name,
+			ModelPkg:    ft.Package,
+			Strict:      data.Strict,
+			MaxIndex:    data.MaxIndex,
+			ApplyOnly:   true,
+			SkipTypes:   true,
+			ExtraParams: ", prefix string",
+		}
+		elemScope := &fieldScope{
+			name:    scope.name + ft.Name + ".",
+			dynamic: true,
+			keys:    scope.keys,
+			types:   append(scope.types[:len(scope.types):len(scope.types)], typeName),
+		}
+		if err = structFields(pkg, fun, nested, input, elemData, elemScope); err != nil {
+			return false, err
+		}
+		data.Elems = append(data.Elems, elemData)
+		addField(ft, fdItem)
+		return true, nil
+	}
+
+	nested := nestedStruct(st, ft)
+	if nested == nil || utils.InStrings(scope.types, typeName) {
+		return false, nil
+	}
+	sub := &fieldScope{
+		path:    scope.path + ft.Name + ".",
+		name:    scope.name + ft.Name + ".",
+		keys:    scope.keys,
+		prefix:  scope.prefix + prefix,
+		dynamic: scope.dynamic,
+		alloc:   scope.alloc,
+		types:   append(scope.types[:len(scope.types):len(scope.types)], typeName),
+	}
+	if ft.Kind == parse.Pointer {
+		fieldName := "obj." + scope.path + ft.Name
+		newExpr, initMethod := structAlloc(nested, ft.Package)
+		alloc := fmt.Sprintf("if %s == nil {\n\t%s = %s", fieldName, fieldName, newExpr)
+		if initMethod != "" {
+			alloc += fmt.Sprintf("\n\t%s.%s()", fieldName, initMethod)
+		}
+		sub.alloc = strings.TrimPrefix(scope.alloc+"\n"+alloc+"\n}", "\n")
+	}
+	return true, structFields(pkg, fun, nested, input, data, sub)
+}
+
+// structAlloc 创建嵌套结构体的表达式，优先级: New<Type>() > Default() 方法 > &Type{}
+func structAlloc(st *parse.StructV2, pkgName string) (newExpr, initMethod string) {
+	if ctor := st.Package.FindConstructor(st.Name); ctor != "" {
+		return fmt.Sprintf("%s.%s()", pkgName, ctor), ""
+	}
+	if st.Package.HasInitMethod(st.Name, "Default") {
+		initMethod = "Default"
+	}
+	return fmt.Sprintf("&%s.%s{}", pkgName, st.Name), initMethod
+}
+
+// fieldWords presence 模式下 bitset 的长度
+func fieldWords(count int) int {
+	if count == 0 {
+		return 1
+	}
+	return (count + 63) / 64
+}
+
+// setConstructor 创建对象的方式，优先级: //map2struct:new 指令 > New<Model>() > Default() 方法 > &Model{}
+func setConstructor(fun *parse.FunctionV2, data *tpl.MapToStructTemplateData) error {
+	model := fun.OutputParam
+	if ctor := fun.Directive("new"); ctor != "" {
+		if strings.Contains(ctor, ".") {
+			data.NewExpr = ctor + "()"
+			return nil
+		}
+		if !model.Package.HasFactory(ctor, model.Name) {
+			return fmt.Errorf("constructor not found. func %s() *%s", ctor, model.Name)
+		}
+		data.NewExpr = fmt.Sprintf("%s.%s()", data.ModelPkg, ctor)
+		return nil
+	}
+
+	// kitex 生成的 NewXxx() 会设置 IDL 中的默认值
+	if ctor := model.Package.FindConstructor(model.Name); ctor != "" {
+		data.NewExpr = fmt.Sprintf("%s.%s()", data.ModelPkg, ctor)
+		return nil
+	}
+
+	data.NewExpr = fmt.Sprintf("&%s.%s{}", data.ModelPkg, data.ModelName)
+	if model.Package.HasInitMethod(model.Name, "Default") {
+		data.InitMethod = "Default"
+	}
+	return nil
+}
+
+// sourceTags 作为 map key 的 tag: 指定了 -tag、//map2struct:tag 指令或者配置文件中的 tag 时只使用该 tag，
+// 否则 http.Header 使用 header tag，其他多值 map 依次使用 form、query tag
+func sourceTags(fun *parse.FunctionV2, input *parse.MapType) []string {
+	switch {
+	case explicitTag(fun) || !input.Multi:
+		return []string{funcTag(fun)}
+	case input.IsHeader():
+		return []string{"header"}
+	default:
+		return []string{"form", "query"}
+	}
+}
+
+// explicitTag 是否指定了 -tag、//map2struct:tag 指令或者配置文件中的 tag
+func explicitTag(fun *parse.FunctionV2) bool {
+	return isFlagSet("tag") || configured["tag"] || fun.Directive("tag") != ""
+}
+
+// funcTag 函数使用的 tag，优先级: -tag 参数 > //map2struct:tag 指令 > 配置文件 > 默认的 json
+func funcTag(fun *parse.FunctionV2) string {
+	if tag := fun.Directive("tag"); tag != "" && !isFlagSet("tag") {
+		return tag
+	}
+	return *tagName
+}
+
+// isFlagSet 命令行是否指定了参数
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		set = set || f.Name == name
+	})
+	return set
+}
+
+// keyTag 字段上第一个存在的 tag，都不存在时使用第一个
+func keyTag(ft *parse.TypeInfo, tags []string) string {
+	for _, tag := range tags {
+		if ft.HasTag(tag) {
+			return tag
+		}
+	}
+	return tags[0]
+}
+
+// lookupExpr 从 map 中取值的 if 语句，取到的值为 tmp；多值 map 的非切片字段和分隔符拼接的切片字段取第一个值
+func lookupExpr(param string, fd *tpl.FieldItem, input *parse.MapType) string {
+	if input.Multi && (fd.GenType != "slice" || fd.Sep != "") {
+		return fmt.Sprintf("if vals := %s[%s]; len(vals) > 0 {\n\ttmp := vals[0]", param, fd.KeyExpr)
+	}
+	return fmt.Sprintf("if tmp, ok := %s[%s]; ok {", param, fd.KeyExpr)
+}
+
+// classifyField 根据字段类型确定生成方式和转换函数
+func classifyField(pkg *parse.PackageV2, ft *parse.TypeInfo, input *parse.MapType, fdItem *tpl.FieldItem) error {
+	defer wrapMultiValues(fdItem, input)
+
+	switch {
+	case isJSONField(ft): // JSON 字符串，任意类型
+		fdItem.GenType = "json"
+
+	case ft.Kind == parse.Array && sepOption(ft) != "": // 分隔符拼接的列表: 1,2,3
+		baseType := ft.Type
+		if !utils.IsBaseType(ft.Type) {
+			var err error
+			if baseType, err = setEnum(pkg, ft, fdItem); err != nil || baseType == "" {
+				return err
 			}
+			fdItem.FieldType = fdItem.TypeConv
+		} else if ft.Type != "string" {
+			fdItem.AssignExpr = convFunc(ft.Type)
+		}
+		fdItem.GenType = "slice"
+		fdItem.Sep = sepOption(ft)
+		// 拆分后的元素都是字符串
+		setTestValues(fdItem, baseType, "string", *strict)
 
-		case ft.IsObjectType(): // 可能是枚举
-			depPkg, err := pkg.GetImportPkg(ft.Package)
-			if err != nil || depPkg == nil {
-				log.Printf("process field failed=%s.%s, err=%v", model.Name, ft.JsonName, err)
-				return false
+	case ft.Kind == parse.Array: // 多值 map 的切片字段
+		if !input.Multi || ft.Type == "byte" {
+			return nil
+		}
+		baseType := ft.Type
+		if !utils.IsBaseType(ft.Type) {
+			// 元素为枚举时按底层类型转换，其他类型留给调用方处理
+			var err error
+			if baseType, err = setEnum(pkg, ft, fdItem); err != nil || baseType == "" {
+				return err
 			}
-			if ti := depPkg.GetTypeIdent(ft.Type); utils.IsBaseType(ti.Type) {
-				fdItem.GenType = "enum"
-				fdItem.TypeConv = fmt.Sprintf("%s.%s", ft.Package, ft.Type)
-				fdItem.AssignExpr = fmt.Sprintf("cast.To%s", utils.ToCap(ti.Type))
+			fdItem.FieldType = fdItem.TypeConv
+		} else {
+			fdItem.TypeEqual = ft.Type == input.ValueType
+			if !fdItem.TypeEqual || input.IsValueInterface {
+				fdItem.AssignExpr = convFunc(ft.Type)
 			}
 		}
+		fdItem.GenType = "slice"
+		setTestValues(fdItem, baseType, input.ValueType, *strict)
 
-		switch fdItem.GenType {
-		case "enum":
-			tplData.EnumFields = append(tplData.EnumFields, fdItem)
-			log.Printf("enum field. field=%s.%s type=%+v", model.Name, ft.Name, ft)
-		case "direct":
-			tplData.DirectFields = append(tplData.DirectFields, fdItem)
-			log.Printf("direct field. field=%s.%s type=%+v", model.Name, ft.Name, ft)
-		case "assign":
-			tplData.AssignFields = append(tplData.AssignFields, fdItem)
-			log.Printf("assign field. field=%s.%s type=%+v", model.Name, ft.Name, ft)
-		default:
-			tplData.OtherFields = append(tplData.OtherFields, fdItem)
-			log.Printf("⚠️ unknown field. field=%s.%s type=%+v", model.Name, ft.Name, ft)
+	case ft.IsBaseType(): // 基本类型
+		if ft.Kind == parse.Pointer {
+			fdItem.GenType = "assign"
+		} else {
+			fdItem.GenType = "direct"
 		}
+		if !fdItem.TypeEqual || input.IsValueInterface {
+			fdItem.AssignExpr = convFunc(ft.Type)
+		}
+		setTestValues(fdItem, ft.Type, input.ValueType, *strict)
 
-		return true
-	})
+	case ft.Package == "time": // time.Duration, time.Time
+		if ft.Type != "Duration" && ft.Type != "Time" {
+			return nil
+		}
+		if ft.Kind == parse.Pointer {
+			fdItem.GenType = "assign"
+		} else {
+			fdItem.GenType = "direct"
+		}
+		fdItem.AssignExpr = "cast.To" + ft.Type
 
-	tplInst := template.New("mapToStruct")
-	if tplInst, err = tplInst.Parse(tpl.MapToStructTemplate); err != nil {
+	case ft.IsObjectType(): // 可能是枚举
+		baseType, err := setEnum(pkg, ft, fdItem)
+		if err != nil {
+			return err
+		}
+		if baseType != "" {
+			fdItem.GenType = "enum"
+			setTestValues(fdItem, baseType, input.ValueType, *strict)
+		}
+	}
+	return nil
+}
+
+// setEnum 底层为基本类型的枚举，设置类型转换和赋值表达式，返回底层类型；不是枚举返回空
+func setEnum(pkg *parse.PackageV2, ft *parse.TypeInfo, fdItem *tpl.FieldItem) (string, error) {
+	depPkg, err := pkg.GetImportPkg(ft.Package)
+	if err != nil || depPkg == nil {
+		return "", fmt.Errorf("load package failed. pkg=%s err=%v", ft.Package, err)
+	}
+	ti := depPkg.GetTypeIdent(ft.Type)
+	if !utils.IsBaseType(ti.Type) {
+		return "", nil
+	}
+	fdItem.TypeConv = fmt.Sprintf("%s.%s", ft.Package, ft.Type)
+	fdItem.EnumBase = ti.Type
+	fdItem.AssignExpr = convFunc(ti.Type)
+	// proto 枚举支持按名字转换: Xxx_value
+	if valueMap := ft.Type + "_value"; depPkg.HasVar(valueMap) {
+		fdItem.AssignExpr = "m2s.ToEnum"
+		fdItem.AssignArgs = fmt.Sprintf(", %s.%s", ft.Package, valueMap)
+	}
+	return ti.Type, nil
+}
+
+// oneofFields proto oneof 字段：查找实现了 isXxx_Yyy() 的包装类型，每个包装类型只有一个字段
+func oneofFields(pkg, modelPkg *parse.PackageV2, ft *parse.TypeInfo, input *parse.MapType) ([]*tpl.FieldItem, error) {
+	var items []*tpl.FieldItem
+	for _, wrapper := range modelPkg.FindMethodTypes(ft.Type) {
+		st, err := modelPkg.FindStruct(wrapper)
+		if err != nil || st == nil || len(st.AstInfo.Fields.List) != 1 {
+			return nil, fmt.Errorf("invalid oneof wrapper. type=%s err=%v", wrapper, err)
+		}
+
+		wft := st.FieldType(st.AstInfo.Fields.List[0])
+		fdItem := &tpl.FieldItem{
+			JsonName:     wft.ProtoName(),
+			FieldName:    ft.Name,
+			FieldType:    wft.Type,
+			TypeEqual:    wft.Type == input.ValueType,
+			WrapperType:  fmt.Sprintf("%s.%s", ft.Package, wrapper),
+			WrapperField: wft.Name,
+		}
+		if err = classifyField(pkg, wft, input, fdItem); err != nil {
+			return nil, err
+		}
+		if fdItem.GenType == "" {
+			log.Printf("⚠️ unsupported oneof field. type=%s field=%s", wrapper, wft.Name)
+		} else {
+			fdItem.GenType = "oneof"
+		}
+		// 单测不覆盖 oneof
+		fdItem.TestValue, fdItem.TestCheck, fdItem.TestCases = "", "", nil
+		items = append(items, fdItem)
+	}
+	return items, nil
+}
+
+// mapToCSV 生成 CSV 的 reader 和 writer，CSV 的值都是字符串，只支持 map[string]string
+func mapToCSV(fun *parse.FunctionV2, data *tpl.MapToStructTemplateData, writer io.Writer) (err error) {
+	if input := fun.InputParam; input.Multi || input.KeyType != "string" || input.ValueType != "string" {
+		log.Printf("⚠️ skip csv, source must be map[string]string. func=%s", fun.Name)
+		return nil
+	}
+	for _, fd := range data.Fields {
+		if fd.Alloc != "" {
+			log.Printf("⚠️ skip csv field, parent may be nil. func=%s field=%s", fun.Name, fd.FieldName)
+			continue
+		}
+		switch {
+		case fd.GenType == "direct", fd.GenType == "assign", fd.GenType == "enum":
+			data.CSVFields = append(data.CSVFields, fd)
+		case fd.GenType == "slice" && fd.Sep != "":
+			data.CSVFields = append(data.CSVFields, fd)
+			data.CSVJoin = true
+		}
+	}
+
+	data.CSVReader = genName(fun, data.BaseName, "%sCSVReader")
+	data.CSVWriter = genName(fun, data.BaseName, "%sCSVWriter")
+	data.CSVHeader = genName(fun, data.BaseName, "%sCSVHeader")
+	data.CSVNewReader = genName(fun, data.BaseName, "New%sCSVReader")
+	data.CSVNewWriter = genName(fun, data.BaseName, "New%sCSVWriter")
+
+	tplInst := template.New("mapToCSV")
+	if tplInst, err = tplInst.Parse(tpl.MapToStructCSVTemplate); err != nil {
 		log.Printf("template parsed failed. err=%v", err)
 		return err
 	}
-	if err := tplInst.Execute(writer, &tplData); err != nil {
+	if err = tplInst.Execute(writer, data); err != nil {
 		log.Printf("template exceute failed. err=%v", err)
 		return err
 	}
 	return nil
 }
 
-func processPrefix(pkg *parse.PackageV2, writer io.Writer) (err error) {
+// 已注册的 map 类型 -> 结构体，同一对类型只能注册一个函数
+var registered = map[string]string{}
+
+func setRegister(fun *parse.FunctionV2, data *tpl.MapToStructTemplateData) {
+	key := data.ParamType + " -> " + data.ModelPkg + "." + data.ModelName
+	if prev, ok := registered[key]; ok {
+		log.Printf("⚠️ skip register, types already registered. func=%s prev=%s types=%s", fun.Name, prev, key)
+		return
+	}
+	registered[key] = fun.Name
+	data.Register = true
+}
+
+// 根据选项生成额外的入参和返回值，返回值顺序: obj, fields, unknown, err
+func setExtraSignature(data *tpl.MapToStructTemplateData) {
+	switch *unknown {
+	case "return", "callback", "error":
+		data.Unknown = *unknown
+	}
+
+	ignored := ""
+	if data.Presence {
+		data.ExtraResults += fmt.Sprintf("fields %s, ", data.FieldsType)
+		data.ExtraValues += "fields, "
+		ignored += "_, "
+	}
+	switch data.Unknown {
+	case "return":
+		data.ExtraResults += "unknown []string, "
+		data.ExtraValues += "unknown, "
+		ignored += "_, "
+	case "callback":
+		data.ExtraParams += ", onUnknown func(key string)"
+		data.ExtraArgs += ", onUnknown"
+		data.TestArgs += ", nil"
+	}
+	data.TestResults = "obj, " + ignored + "err"
+	data.ApplyResults = ignored + "err"
+	data.FuzzResults = "_, " + ignored + "_"
+}
+
+// 数值和布尔类型使用 m2s 做精确转换（支持 json.Number，检查精度丢失）
+func convFunc(typ string) string {
+	if utils.IsNumberType(typ) || typ == "bool" {
+		return fmt.Sprintf("m2s.To%s", utils.ToCap(typ))
+	}
+	return fmt.Sprintf("cast.To%s", utils.ToCap(typ))
+}
+
+func processPrefix(pkg *parse.PackageV2, prefix string, imports []string, writer io.Writer) (err error) {
 	tplData := tpl.MapToStructTemplateData{
 		Package: pkg.Name,
+		Imports: imports,
 	}
 
 	tplInst := template.New("mapToStruct")
-	if tplInst, err = tplInst.Parse(tpl.MapToStructPrefix); err != nil {
+	if tplInst, err = tplInst.Parse(prefix); err != nil {
 		log.Printf("template parsed failed. err=%v", err)
 		return err
 	}
@@ -214,17 +1080,64 @@ func processPrefix(pkg *parse.PackageV2, writer io.Writer) (err error) {
 	return nil
 }
 
+// configDir 查找配置文件的起始目录: 输入文件所在的目录，没有输入文件时为当前目录
+func configDir() string {
+	file := *input
+	if file == "" {
+		file = strings.Trim(os.Getenv("GOFILE"), `"`)
+	}
+	cwd, _ := os.Getwd()
+	if file == "" {
+		return cwd
+	}
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(cwd, file)
+	}
+	return filepath.Dir(file)
+}
+
 func saveOutput(data []byte, file string) error {
 	out, err := imports.Process(file, data, nil)
 	if err != nil {
 		log.Printf("import error. err=%v", err)
 		return err
 	}
+	if *check {
+		return checkOutput(out, file)
+	}
 	return ioutil.WriteFile(file, out, 0600)
 }
 
+// 与生成结果不一致的文件
+var staleFiles []string
+
+// checkOutput -check 时不写入文件，与磁盘上的文件比较，不一致时输出 diff
+func checkOutput(data []byte, file string) error {
+	old, err := ioutil.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if bytes.Equal(old, data) {
+		return nil
+	}
+	name := relPath(file)
+	fmt.Print(utils.UnifiedDiff(name, old, data))
+	staleFiles = append(staleFiles, name)
+	return nil
+}
+
+// relPath 相对于当前目录的路径，用于输出
+func relPath(file string) string {
+	if cwd, _ := os.Getwd(); cwd != "" {
+		if rel, err := filepath.Rel(cwd, file); err == nil {
+			return rel
+		}
+	}
+	return file
+}
+
 func newOutputName(file string) string {
 	fname := filepath.Base(file)
 	fname = strings.TrimSuffix(fname, ".go")
-	return utils.ToSnakeCase(fname) + "_gen.go"
+	return utils.ToSnakeCase(fname) + *genSuffix
 }
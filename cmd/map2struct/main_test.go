@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestMain 环境变量 MAP2STRUCT_MAIN=1 时作为 map2struct 命令运行，测试在子进程中调用 main
+func TestMain(m *testing.M) {
+	if os.Getenv("MAP2STRUCT_MAIN") == "1" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runMain 在 dir 中运行 map2struct，非 0 退出时 err 为 *exec.ExitError
+func runMain(t *testing.T, dir string, args ...string) (string, error) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "MAP2STRUCT_MAIN=1")
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+// generateFile 生成 testdata/<name>/<name>.go 到临时目录，返回生成的代码
+func generateFile(t *testing.T, name string, args ...string) string {
+	t.Helper()
+	outDir := t.TempDir()
+	args = append([]string{"-input", name + ".go", "-output", outDir}, args...)
+	if out, err := runMain(t, filepath.Join("testdata", name), args...); err != nil {
+		t.Fatalf("generate failed. err=%v out=%s", err, out)
+	}
+	data, err := os.ReadFile(filepath.Join(outDir, name+"_gen.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// funcBody 生成代码中函数的定义
+func funcBody(t *testing.T, code, name string) string {
+	t.Helper()
+	start := strings.Index(code, "func "+name+"(")
+	if start < 0 {
+		t.Fatalf("func %s not generated", name)
+	}
+	end := strings.Index(code[start:], "\n}\n")
+	return code[start : start+end]
+}
+
+func TestDirectivePriority(t *testing.T) {
+	cases := []struct {
+		args []string
+		fun  string
+		want []string
+		not  []string
+	}{
+		// 默认的 thrift tag < tag 指令 < field 指令
+		{nil, "genApplyDefaultItem", []string{`src["title"]`, `src["lang"]`}, []string{`src["name"]`}},
+		{nil, "genApplyJSONItem", []string{`src["name"]`, `src["lang"]`}, []string{`src["title"]`}},
+		{nil, "genApplyThriftItem", []string{`src["title"]`}, []string{`src["name"]`}},
+		{nil, "genApplyHeadingItem", []string{`src["item_heading"]`, `src["item_item_id"]`}, []string{`src["item_title"]`, `lang"]`}},
+		// 命令行参数优先于 tag 指令，field 指令仍然生效
+		{[]string{"-tag=json"}, "genApplyThriftItem", []string{`src["name"]`}, []string{`src["title"]`}},
+		{[]string{"-tag=json"}, "genApplyHeadingItem", []string{`src["item_heading"]`}, []string{`src["item_name"]`}},
+	}
+	for _, c := range cases {
+		body := funcBody(t, generateFile(t, "directive", c.args...), c.fun)
+		for _, s := range c.want {
+			if !strings.Contains(body, s) {
+				t.Errorf("args=%v func=%s missing %s\n%s", c.args, c.fun, s, body)
+			}
+		}
+		for _, s := range c.not {
+			if strings.Contains(body, s) {
+				t.Errorf("args=%v func=%s unexpected %s\n%s", c.args, c.fun, s, body)
+			}
+		}
+	}
+}
+
+func TestThriftRequired(t *testing.T) {
+	// 没有 -strict 也检查 thrift 的 required 字段，optional 字段不检查
+	code := generateFile(t, "directive")
+	body := funcBody(t, code, "genMapToDefaultItem")
+	for _, key := range []string{"item_id", "title"} {
+		if !strings.Contains(body, fmt.Sprintf("&m2s.KeyError{Key: %q, Err: m2s.ErrMissingKey}", key)) {
+			t.Errorf("required key %s not checked\n%s", key, body)
+		}
+	}
+	for _, key := range []string{"status", "score", "lang"} {
+		if strings.Contains(body, fmt.Sprintf("%q", key)) {
+			t.Errorf("optional key %s checked\n%s", key, body)
+		}
+	}
+}
+
+func TestMaxIndex(t *testing.T) {
+	cases := []struct {
+		args []string
+		want []string
+	}{
+		// 非严格模式忽略超出的下标，并且作为未知的 key
+		{[]string{"-maxindex=10", "-unknown=return"}, []string{
+			`ok && idx < 10 && idx >= indexLen[1]`,
+			`m2s.KeyIndex(key, "tags.", ".", nil); ok && idx < 10 {`,
+		}},
+		{[]string{"-maxindex=10", "-strict"}, []string{
+			`ok && idx >= 10 {`,
+			`m2s.ErrIndexRange`,
+		}},
+	}
+	for _, c := range cases {
+		body := funcBody(t, generateFile(t, "indexed", c.args...), "genApplyFlatBook")
+		for _, s := range c.want {
+			if !strings.Contains(body, s) {
+				t.Errorf("args=%v missing %s\n%s", c.args, s, body)
+			}
+		}
+	}
+	if out, err := runMain(t, filepath.Join("testdata", "indexed"), "-input", "indexed.go", "-output", t.TempDir(), "-maxindex=0"); err == nil {
+		t.Errorf("maxindex=0 should fail. out=%s", out)
+	}
+}
+
+func TestScanTag(t *testing.T) {
+	cases := []struct {
+		args []string
+		want string
+		not  string
+	}{
+		// tag 指令优先于扫描函数默认的 db tag，命令行参数优先于 tag 指令
+		{nil, `case "title":`, `case "name":`},
+		{[]string{"-tag=json"}, `case "name":`, `case "title":`},
+	}
+	for _, c := range cases {
+		body := funcBody(t, generateFile(t, "directive", c.args...), "genApiItemInfoColumnIndex")
+		if !strings.Contains(body, c.want) || strings.Contains(body, c.not) {
+			t.Errorf("args=%v want %s not %s\n%s", c.args, c.want, c.not, body)
+		}
+	}
+}
+
+func TestDirectiveUnused(t *testing.T) {
+	out, err := runMain(t, filepath.Join("testdata", "unused"), "-input", "unused.go", "-output", t.TempDir())
+	if _, ok := err.(*exec.ExitError); !ok {
+		t.Fatalf("want exit error. err=%v out=%s", err, out)
+	}
+	if !strings.Contains(out, "field in directive not found. func=MapToItem fields=Langs,Titel") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestNonMapStub(t *testing.T) {
+	for _, args := range [][]string{nil, {"-match=^MapTo(\\w+)$"}} {
+		code := generateFile(t, "stubs", args...)
+		funcBody(t, code, "genMapToItem")
+		if strings.Contains(code, "Author") {
+			t.Errorf("args=%v non-map stub generated:\n%s", args, code)
+		}
+	}
+}
+
+func TestConfigFromRoot(t *testing.T) {
+	if out, err := runMain(t, "../..", "-check", "./example/settings"); err != nil {
+		t.Fatalf("check settings failed. err=%v out=%s", err, out)
+	}
+}
+
+func TestConfigPerPackage(t *testing.T) {
+	// strict 的配置文件不能影响后面没有配置文件的 plain
+	out, err := runMain(t, ".", "-check", "./testdata/config/strict", "./testdata/config/plain")
+	if err != nil {
+		t.Fatalf("check failed. err=%v out=%s", err, out)
+	}
+	out, err = runMain(t, ".", "-check", "./testdata/config/plain", "./testdata/config/strict")
+	if err != nil {
+		t.Fatalf("check failed. err=%v out=%s", err, out)
+	}
+}
+
+func TestCheckExitStatus(t *testing.T) {
+	dir := filepath.Join("testdata", "config", "plain")
+	file := filepath.Join(dir, "plain_gen.go")
+	before, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		dir   string
+		args  []string
+		stale bool
+	}{
+		{".", []string{"-check", "./" + dir}, false},
+		{dir, []string{"-check", "-input", "plain.go"}, false},
+		{".", []string{"-check", "-strict", "./" + dir}, true},
+		{dir, []string{"-check", "-strict", "-input", "plain.go"}, true},
+	}
+	for _, c := range cases {
+		out, err := runMain(t, c.dir, c.args...)
+		if !c.stale {
+			if err != nil {
+				t.Errorf("check failed. args=%v err=%v out=%s", c.args, err, out)
+			}
+			continue
+		}
+		exitErr, ok := err.(*exec.ExitError)
+		if !ok || exitErr.ExitCode() != 1 {
+			t.Errorf("check should exit 1. args=%v err=%v out=%s", c.args, err, out)
+		}
+		if !strings.Contains(out, "--- a/") || !strings.Contains(out, "generated files are out of date") {
+			t.Errorf("check should print diff. args=%v out=%s", c.args, out)
+		}
+	}
+
+	// -check 不写入文件
+	after, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Errorf("check modified file. file=%s", file)
+	}
+}
+
+func TestCheckGenerateArgs(t *testing.T) {
+	// 包模式使用 //go:generate 的参数（-strict），与命令行参数无关
+	dir := filepath.Join("testdata", "generate")
+	if out, err := runMain(t, ".", "-check", "./"+dir); err != nil {
+		t.Errorf("check failed. err=%v out=%s", err, out)
+	}
+	// 单个文件使用命令行参数，没有 -strict 时不是最新的
+	if out, err := runMain(t, dir, "-check", "-input", "generate.go"); err == nil {
+		t.Errorf("check should fail without -strict. out=%s", out)
+	}
+	// 所有示例都按各自的 //go:generate 参数生成
+	if out, err := runMain(t, filepath.Join("..", ".."), "-check", "./example/..."); err != nil {
+		t.Errorf("check examples failed. err=%v out=%s", err, out)
+	}
+}
+
+func TestSplitGenerateArgs(t *testing.T) {
+	cases := []struct {
+		line string
+		want []string
+		err  bool
+	}{
+		{"map2struct -strict -input $GOFILE", []string{"map2struct", "-strict", "-input", "$GOFILE"}, false},
+		{" map2struct\t-tag  json ", []string{"map2struct", "-tag", "json"}, false},
+		{`map2struct -prefix "a b" -sep "\t"`, []string{"map2struct", "-prefix", "a b", "-sep", "\t"}, false},
+		{`map2struct -prefix "a\"b"`, []string{"map2struct", "-prefix", `a"b`}, false},
+		{`map2struct -prefix "ab`, nil, true},
+	}
+	for _, c := range cases {
+		got, err := splitGenerateArgs(c.line)
+		if (err != nil) != c.err {
+			t.Errorf("unexpected error. line=%s err=%v", c.line, err)
+			continue
+		}
+		if fmt.Sprint(got) != fmt.Sprint(c.want) || len(got) != len(c.want) {
+			t.Errorf("split mismatch. line=%s got=%q want=%q", c.line, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"text/template"
+
+	"github.com/adyzng/gotool/parse"
+	"github.com/adyzng/gotool/tpl"
+	"github.com/adyzng/gotool/utils"
+)
+
+// mapToStructMerge 多个 map 参数: 每个参数生成一个 Apply 函数，后面的参数优先级更高；
+// 每个 key 只从存在该 key 的优先级最高的参数转换，优先级低的参数中被覆盖的值不会转换
+func mapToStructMerge(pkg *parse.PackageV2, fun *parse.FunctionV2, writer io.Writer) (err error) {
+	if *genTest || *register || *genCSV || *genSlice {
+		log.Printf("⚠️ skip -test/-register/-csv/-slice for multiple sources. func=%s", fun.Name)
+	}
+	baseName := stubBase(fun.Name)
+
+	var (
+		dataList []*tpl.MapToStructTemplateData
+		fields   []*tpl.FieldItem
+		required []string
+	)
+	mergeData := &tpl.MergeTemplateData{
+		FuncName:    genName(fun, baseName, ""),
+		SourcesType: genName(fun, baseName, "%sSources"),
+	}
+	if err = claimName(mergeData.FuncName, fun); err != nil {
+		return err
+	}
+	for idx, input := range fun.InputParams {
+		name := input.Name
+		if name == "" || name == "_" {
+			name = fmt.Sprintf("src%d", idx)
+		}
+		if utils.InStrings(sourceNames(mergeData), name) {
+			return fmt.Errorf("duplicate param name. func=%s name=%s", fun.Name, name)
+		}
+
+		data, err := newTemplateData(pkg, fun, input, name, genName(fun, baseName, "Apply%sFrom"+utils.ToCap(name)))
+		if err != nil {
+			return err
+		}
+		data.ApplyOnly = true
+		data.SkipTypes = idx > 0
+		dataList = append(dataList, data)
+
+		for _, fd := range data.Fields {
+			if !containsField(fields, fd.FieldConst) {
+				fields = append(fields, fd)
+			}
+		}
+		for _, key := range data.RequiredKeys {
+			if !utils.InStrings(required, key) {
+				required = append(required, key)
+			}
+		}
+		// Apply 函数不检查必须的 key
+		data.RequiredKeys = nil
+
+		src := &tpl.MergeSource{Name: name, ParamType: data.ParamType, ApplyName: data.ApplyName}
+		var results []string
+		if data.Presence {
+			src.FieldsVar = fmt.Sprintf("fields%d", idx)
+			results = append(results, src.FieldsVar)
+		}
+		if data.Unknown == "return" {
+			src.Unknown = fmt.Sprintf("unknown%d", idx)
+			results = append(results, src.Unknown)
+		}
+		if len(results) > 0 {
+			src.Results = strings.Join(results, ", ") + ", err :="
+		} else {
+			src.Results = "err ="
+		}
+		mergeData.Sources = append(mergeData.Sources, src)
+	}
+
+	names := sourceNames(mergeData)
+	for idx, src := range mergeData.Sources {
+		if src.Higher = names[idx+1:]; len(src.Higher) > 0 {
+			src.Own = src.Name + "Own"
+		}
+		if utils.InStrings(names, src.Own) {
+			return fmt.Errorf("duplicate param name. func=%s name=%s", fun.Name, src.Own)
+		}
+	}
+
+	first := dataList[0]
+	mergeData.ModelPkg, mergeData.ModelName = first.ModelPkg, first.ModelName
+	mergeData.NewExpr, mergeData.InitMethod = first.NewExpr, first.InitMethod
+	mergeData.Presence, mergeData.Unknown = first.Presence, first.Unknown
+	mergeData.FieldEnum, mergeData.FieldsType = first.FieldEnum, first.FieldsType
+	mergeData.FieldCount = len(fields)
+	mergeData.ExtraParams, mergeData.ExtraArgs = first.ExtraParams, first.ExtraArgs
+	if mergeData.Presence {
+		mergeData.ExtraResults += fmt.Sprintf("fields %s, sources %s, ", mergeData.FieldsType, mergeData.SourcesType)
+		mergeData.ExtraValues += "fields, sources, "
+	}
+	if mergeData.Unknown == "return" {
+		mergeData.ExtraResults += "unknown []string, "
+		mergeData.ExtraValues += "unknown, "
+	}
+	for _, key := range required {
+		mergeData.RequiredChecks = append(mergeData.RequiredChecks, requiredCheck(mergeData, key))
+	}
+
+	// presence 类型包含所有 src 能处理的字段
+	first.Fields = fields
+	first.FieldWords = fieldWords(len(fields))
+
+	tplInst := template.New("mapToStruct")
+	if tplInst, err = tplInst.Parse(tpl.MapToStructTemplate); err != nil {
+		log.Printf("template parsed failed. err=%v", err)
+		return err
+	}
+	for _, data := range dataList {
+		if err = executeTemplate(tplInst, writer, data); err != nil {
+			return err
+		}
+	}
+
+	mergeInst := template.New("mapToStructMerge")
+	if mergeInst, err = mergeInst.Parse(tpl.MapToStructMergeTemplate); err != nil {
+		log.Printf("template parsed failed. err=%v", err)
+		return err
+	}
+	if err = mergeInst.Execute(writer, mergeData); err != nil {
+		log.Printf("template exceute failed. err=%v", err)
+		return err
+	}
+	return nil
+}
+
+func sourceNames(data *tpl.MergeTemplateData) []string {
+	var names []string
+	for _, src := range data.Sources {
+		names = append(names, src.Name)
+	}
+	return names
+}
+
+func containsField(fields []*tpl.FieldItem, fieldConst string) bool {
+	for _, fd := range fields {
+		if fd.FieldConst == fieldConst {
+			return true
+		}
+	}
+	return false
+}
+
+// requiredCheck 所有 src 中都不存在 key 时返回错误
+func requiredCheck(data *tpl.MergeTemplateData, key string) string {
+	var sb strings.Builder
+	for _, src := range data.Sources {
+		fmt.Fprintf(&sb, "if _, ok := %s[%q]; !ok {\n", src.Name, key)
+	}
+	fmt.Fprintf(&sb, "return nil, %s&m2s.KeyError{Key: %q, Err: m2s.ErrMissingKey}\n", data.ExtraValues, key)
+	sb.WriteString(strings.Repeat("}\n", len(data.Sources)))
+	return strings.TrimSuffix(sb.String(), "\n")
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"log"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/adyzng/gotool/parse"
+	"github.com/adyzng/gotool/utils"
+)
+
+// nameData 命名模板的参数，比如: gen{{.Name}}, {{.Name}}Gen, Decode{{.Model}}
+// 辅助函数和类型也使用同一个模板，三个参数都按格式加上后缀，比如 Apply 函数为 Apply<Base>、Apply<Model>
+type nameData struct {
+	Name  string // 函数名，比如: MapToBook
+	Base  string // 去掉前缀的函数名，比如: Book
+	Model string // 结构体名，比如: ApiBookInfo
+}
+
+var (
+	nameTpl  *template.Template
+	funcExpr *regexp.Regexp
+	genNames = map[string]string{} // 已生成的函数名 -> 声明的函数名
+)
+
+// initNaming 解析函数匹配规则和命名模板
+func initNaming(match, naming string) (err error) {
+	funcExpr = nil
+	if match != "" {
+		if funcExpr, err = regexp.Compile(match); err != nil {
+			return fmt.Errorf("invalid param. match=%s err=%v", match, err)
+		}
+	}
+	if nameTpl, err = template.New("name").Parse(naming); err != nil {
+		return fmt.Errorf("invalid param. name=%s err=%v", naming, err)
+	}
+
+	// 引用了不存在的参数，或者生成的不是合法的标识符
+	buf := &bytes.Buffer{}
+	if err = nameTpl.Execute(buf, &nameData{Name: "MapToBook", Base: "Book", Model: "Book"}); err != nil {
+		return fmt.Errorf("invalid param. name=%s err=%v", naming, err)
+	}
+	if !token.IsIdentifier(buf.String()) {
+		return fmt.Errorf("invalid param, not an identifier. name=%s result=%s", naming, buf.String())
+	}
+	return nil
+}
+
+// findStubList 查找 -prefix 或 -match 匹配的转换函数
+func findStubList(pkg *parse.PackageV2) (map[string]*parse.FunctionV2, error) {
+	var (
+		fnList map[string]*parse.FunctionV2
+		err    error
+	)
+	if funcExpr == nil {
+		fnList, err = pkg.FindFuncList(*funcPrefix)
+	} else {
+		fnList, err = pkg.FindFuncMatch(funcExpr.MatchString)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for name, fun := range fnList {
+		// 生成的函数也可能匹配，比如: -name={{.Name}}Gen
+		if strings.HasSuffix(fun.File, *genSuffix) {
+			delete(fnList, name)
+			continue
+		}
+		// 前缀或正则还可能匹配到其他函数，比如: func MapToAuthor(id int64)，只保留入参为 map 的
+		if fun.InputParam == nil {
+			if funcExpr == nil {
+				log.Printf("⚠️ skip func, input must be a map. func=%s", name)
+			}
+			delete(fnList, name)
+		}
+	}
+	return fnList, nil
+}
+
+// stubBase 去掉前缀的函数名；-match 有分组时为第一个分组
+func stubBase(name string) string {
+	if funcExpr == nil {
+		return strings.TrimPrefix(name, *funcPrefix)
+	}
+	if ss := funcExpr.FindStringSubmatch(name); len(ss) > 1 && ss[1] != "" {
+		return ss[1]
+	}
+	return name
+}
+
+// genName 按命名模板生成名字，role 为辅助函数和类型的格式，比如: "Apply%s", "%sField"，为空时是函数本身
+func genName(fun *parse.FunctionV2, base, role string) string {
+	data := nameData{Name: fun.Name, Base: base, Model: fun.OutputType.TypeName}
+	if role != "" {
+		data = nameData{
+			Name:  fmt.Sprintf(role, base),
+			Base:  fmt.Sprintf(role, base),
+			Model: fmt.Sprintf(role, data.Model),
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	if err := nameTpl.Execute(buf, &data); err != nil {
+		log.Fatalf("execute name template failed. func=%s err=%v", fun.Name, err)
+	}
+	if *exported {
+		return utils.ToCap(buf.String())
+	}
+	return utils.ToUnCap(buf.String())
+}
+
+// claimName 生成的函数名不能重复，比如多个函数的结构体相同而命名模板只使用了 {{.Model}}
+func claimName(name string, fun *parse.FunctionV2) error {
+	if prev, ok := genNames[name]; ok {
+		return fmt.Errorf("generated name conflict. name=%s func=%s prev=%s", name, fun.Name, prev)
+	}
+	genNames[name] = fun.Name
+	return nil
+}
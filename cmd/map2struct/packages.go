@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"github.com/adyzng/gotool/parse"
+	"github.com/adyzng/gotool/utils"
+)
+
+// generatePackages 包模式: map2struct [flags] ./... 或 import 路径，为每个文件（-onefile 时为每个包）生成 _gen.go
+func generatePackages(patterns []string) error {
+	if *input != "" || *output != "" || *typeList != "" {
+		return fmt.Errorf("-input, -output and -type are not supported with packages. packages=%v", patterns)
+	}
+	dirs, err := parse.ListPackageDirs(patterns)
+	if err != nil {
+		return err
+	}
+
+	pkgParser := parse.NewPkgParser()
+	for _, dir := range dirs {
+		// -check 时有 //go:generate map2struct 指令的包使用指令中的参数
+		if *check {
+			lines, err := findGenerateLines(dir)
+			if err != nil {
+				return fmt.Errorf("find go:generate failed. dir=%s err=%v", dir, err)
+			}
+			if len(lines) > 0 {
+				if err = checkGenerateLines(dir, lines); err != nil {
+					return fmt.Errorf("check failed. dir=%s err=%v", dir, err)
+				}
+				continue
+			}
+		}
+
+		// 每个包使用自己目录中的配置文件
+		if err = applyConfig(dir); err != nil {
+			return fmt.Errorf("load config failed. dir=%s err=%v", dir, err)
+		}
+		pkg, err := pkgParser.ParsePackage(dir)
+		if err != nil {
+			return fmt.Errorf("parse package failed. dir=%s err=%v", dir, err)
+		}
+		funcs, err := findFuncList(pkg)
+		if err != nil {
+			return fmt.Errorf("parse function failed. dir=%s err=%v", dir, err)
+		}
+		if len(funcs) == 0 {
+			continue
+		}
+
+		log.Printf("package: %s", dir)
+		resetPackage()
+		for _, out := range packageOutputs(pkg, dir, funcs) {
+			if err = generate(pkg, out); err != nil {
+				return fmt.Errorf("generate failed. dir=%s err=%v", dir, err)
+			}
+		}
+	}
+	return nil
+}
+
+// packageOutputs 按函数声明所在的文件分组，-onefile 时整个包写入 <包名>_gen.go
+func packageOutputs(pkg *parse.PackageV2, dir string, funcs []*parse.FunctionV2) []*genOutput {
+	var list []*genOutput
+	outputs := map[string]*genOutput{}
+	for _, fun := range funcs {
+		file := filepath.Join(dir, utils.ToSnakeCase(pkg.Name)+*genSuffix)
+		if !*oneFile {
+			file = filepath.Join(dir, newOutputName(fun.File))
+		}
+		out, ok := outputs[file]
+		if !ok {
+			out = &genOutput{file: file}
+			outputs[file] = out
+			list = append(list, out)
+		}
+		out.funcs = append(out.funcs, fun)
+	}
+	return list
+}
+
+// resetPackage 生成的名字、注册的类型、数据库扫描的辅助函数在同一个包中不能重复，每个包重新开始
+func resetPackage() {
+	genNames = map[string]string{}
+	registered = map[string]string{}
+	scanHelpers = map[string]bool{}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"io"
+	"log"
+	"strings"
+	"text/template"
+
+	"github.com/adyzng/gotool/parse"
+	"github.com/adyzng/gotool/tpl"
+)
+
+// 扫描数据库查询结果的函数:
+// func ScanXxx(rows *sql.Rows) ([]*model.Xxx, error)
+// func ScanXxxRow(row *sql.Row) (*model.Xxx, error)
+const scanPrefix = "Scan"
+
+// findScanFuncList 查找第一个参数为 *sql.Rows 或 *sql.Row 的扫描函数
+func findScanFuncList(pkg *parse.PackageV2) ([]*parse.FunctionV2, error) {
+	fnList, err := pkg.FindFuncList(scanPrefix)
+	if err != nil {
+		return nil, err
+	}
+	var list []*parse.FunctionV2
+	for _, fun := range fnList {
+		if fun.InputParam == nil && fun.OutputParam != nil && scanParam(fun) != "" && !strings.HasSuffix(fun.File, *genSuffix) {
+			list = append(list, fun)
+		}
+	}
+	sortFuncs(list)
+	return list, nil
+}
+
+// scanParam 扫描函数的参数类型: Rows 或 Row
+func scanParam(fun *parse.FunctionV2) string {
+	if len(fun.Params) == 0 {
+		return ""
+	}
+	if param := fun.Params[0]; param.Pointer && param.Package == "sql" {
+		switch param.TypeName {
+		case "Rows", "Row":
+			return param.TypeName
+		}
+	}
+	return ""
+}
+
+// scanTag 扫描函数使用的 tag，没有指定 -tag、//map2struct:tag 指令或者配置文件中的 tag 时使用 db
+func scanTag(fun *parse.FunctionV2) string {
+	if explicitTag(fun) {
+		return funcTag(fun)
+	}
+	return "db"
+}
+
+// 已生成 Columns/Index/Set 的结构体
+var scanHelpers = map[string]bool{}
+
+func scanRows(pkg *parse.PackageV2, fun *parse.FunctionV2, writer io.Writer) (err error) {
+	model := fun.OutputParam
+	baseName := strings.TrimPrefix(fun.Name, scanPrefix)
+	ctorData := tpl.MapToStructTemplateData{
+		ModelPkg:  fun.OutputType.Package,
+		ModelName: fun.OutputType.TypeName,
+	}
+	if err = setConstructor(fun, &ctorData); err != nil {
+		log.Printf("process constructor failed. func=%s err=%v", fun.Name, err)
+		return err
+	}
+
+	tplData := tpl.ScanTemplateData{
+		ModelPkg:    ctorData.ModelPkg,
+		ModelName:   ctorData.ModelName,
+		NewExpr:     ctorData.NewExpr,
+		InitMethod:  ctorData.InitMethod,
+		ColumnsName: genName(fun, model.Name, "%sColumns"),
+		IndexName:   genName(fun, model.Name, "%sColumnIndex"),
+		SetName:     genName(fun, model.Name, "Set%sField"),
+	}
+	switch scanParam(fun) {
+	case "Rows":
+		if !fun.OutputType.Slice {
+			return fmt.Errorf("result must be a slice. func %s(rows *sql.Rows) ([]*%s, error)", fun.Name, model.Name)
+		}
+		tplData.RowsName = genName(fun, baseName, "")
+	case "Row":
+		tplData.RowName = genName(fun, baseName, "")
+	}
+	if err = claimName(genName(fun, baseName, ""), fun); err != nil {
+		return err
+	}
+
+	helperKey := ctorData.ModelPkg + "." + ctorData.ModelName
+	tplData.Helpers = !scanHelpers[helperKey]
+	scanHelpers[helperKey] = true
+
+	input := &parse.MapType{KeyType: "string", ValueType: "interface{}", IsValueInterface: true}
+	tag := scanTag(fun)
+	model.EnumField(func(fd *ast.Field) bool {
+		ft := model.FieldType(fd)
+		if (!ft.IsExported() && model.Package != pkg) || strings.HasPrefix(ft.Name, "XXX_") {
+			return true
+		}
+		key := ft.KeyName(tag)
+		if key == "" {
+			return true
+		}
+		fdItem := &tpl.FieldItem{
+			JsonName:  key,
+			FieldName: ft.Name,
+			FieldType: ft.Type,
+			IsPointer: ft.Kind == parse.Pointer,
+		}
+		if err = classifyField(pkg, ft, input, fdItem); err != nil {
+			log.Printf("process field failed=%s.%s, err=%v", model.Name, ft.Name, err)
+			return false
+		}
+		switch fdItem.GenType {
+		case "direct", "assign", "enum":
+			tplData.Fields = append(tplData.Fields, fdItem)
+		default:
+			tplData.OtherFields = append(tplData.OtherFields, fdItem)
+			log.Printf("⚠️ unknown field. field=%s.%s type=%+v", model.Name, ft.Name, ft)
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	tplInst := template.New("scan")
+	if tplInst, err = tplInst.Parse(tpl.ScanTemplate); err != nil {
+		log.Printf("template parsed failed. err=%v", err)
+		return err
+	}
+	if err = tplInst.Execute(writer, &tplData); err != nil {
+		log.Printf("template exceute failed. err=%v", err)
+		return err
+	}
+	return nil
+}
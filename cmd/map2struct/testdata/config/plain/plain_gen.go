@@ -0,0 +1,35 @@
+// Auto generated code, DO NOT EDIT.
+
+package plain
+
+import (
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+)
+
+func genMapToServer(src map[string]string) (obj *model.ServerConfig, err error) {
+	obj = model.NewServerConfig()
+	if err = genApplyServer(src, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// genApplyServer 只覆盖 src 中存在的字段
+func genApplyServer(src map[string]string, obj *model.ServerConfig) (err error) {
+	// 直接赋值的字段
+	if tmp, ok := src["host"]; ok {
+		obj.Host = tmp
+	}
+	if tmp, ok := src["port"]; ok {
+		obj.Port = m2s.ToInt(tmp)
+	}
+	if tmp, ok := src["timeout_ms"]; ok {
+		obj.TimeoutMs = m2s.ToInt64(tmp)
+	}
+	if tmp, ok := src["Debug"]; ok {
+		obj.Debug = m2s.ToBool(tmp)
+	}
+
+	return err
+}
@@ -0,0 +1,9 @@
+package strict
+
+import (
+	"github.com/adyzng/gotool/example/model"
+)
+
+func MapToServer(src map[string]string) (*model.ServerConfig, error) {
+	return nil, nil
+}
@@ -0,0 +1,41 @@
+// Auto generated code, DO NOT EDIT.
+
+package strict
+
+import (
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+)
+
+func decodeServer(src map[string]string) (obj *model.ServerConfig, err error) {
+	obj = model.NewServerConfig()
+	if err = decodeApplyServer(src, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// decodeApplyServer 只覆盖 src 中存在的字段
+func decodeApplyServer(src map[string]string, obj *model.ServerConfig) (err error) {
+	// 直接赋值的字段
+	if tmp, ok := src["host"]; ok {
+		obj.Host = tmp
+	}
+	if tmp, ok := src["port"]; ok {
+		if obj.Port, err = m2s.ToIntE(tmp); err != nil {
+			return &m2s.KeyError{Key: "port", Err: err}
+		}
+	}
+	if tmp, ok := src["timeout_ms"]; ok {
+		if obj.TimeoutMs, err = m2s.ToInt64E(tmp); err != nil {
+			return &m2s.KeyError{Key: "timeout_ms", Err: err}
+		}
+	}
+	if tmp, ok := src["Debug"]; ok {
+		if obj.Debug, err = m2s.ToBoolE(tmp); err != nil {
+			return &m2s.KeyError{Key: "Debug", Err: err}
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,43 @@
+package directive
+
+import (
+	"database/sql"
+
+	"github.com/adyzng/gotool/example/model"
+)
+
+// MapToDefaultItem 没有指令，thrift 结构体默认使用 thrift tag: title
+func MapToDefaultItem(src map[string]string) (*model.ApiItemInfo, error) {
+	return nil, nil
+}
+
+// MapToJSONItem tag 指令: name
+//
+//map2struct:tag json
+func MapToJSONItem(src map[string]string) (*model.ApiItemInfo, error) {
+	return nil, nil
+}
+
+// MapToThriftItem tag 指令: title
+//
+//map2struct:tag thrift
+func MapToThriftItem(src map[string]string) (*model.ApiItemInfo, error) {
+	return nil, nil
+}
+
+// MapToHeadingItem field 指令优先于 tag，忽略 Lang
+//
+//map2struct:tag thrift
+//map2struct:prefix item_
+//map2struct:field Title=heading
+//map2struct:ignore Lang
+func MapToHeadingItem(src map[string]string) (*model.ApiItemInfo, error) {
+	return nil, nil
+}
+
+// ScanThriftItem 扫描函数的 tag 指令: title
+//
+//map2struct:tag thrift
+func ScanThriftItem(rows *sql.Rows) ([]*model.ApiItemInfo, error) {
+	return nil, nil
+}
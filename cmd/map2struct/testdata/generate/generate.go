@@ -0,0 +1,11 @@
+package generate
+
+import (
+	"github.com/adyzng/gotool/example/model"
+)
+
+//go:generate map2struct -strict -input $GOFILE
+
+func MapToServer(src map[string]string) (*model.ServerConfig, error) {
+	return nil, nil
+}
@@ -0,0 +1,10 @@
+package indexed
+
+import (
+	"github.com/adyzng/gotool/example/model"
+)
+
+// MapToFlatBook 带下标的 key: chapters.0.title, tags.0
+func MapToFlatBook(src map[string]string) (*model.FlatBook, error) {
+	return nil, nil
+}
@@ -0,0 +1,14 @@
+package stubs
+
+import (
+	"github.com/adyzng/gotool/example/model"
+)
+
+func MapToItem(src map[string]string) (*model.ApiItemInfo, error) {
+	return nil, nil
+}
+
+// MapToAuthor 入参不是 map，不是转换函数
+func MapToAuthor(id int64) (*model.Author, error) {
+	return nil, nil
+}
@@ -0,0 +1,13 @@
+package unused
+
+import (
+	"github.com/adyzng/gotool/example/model"
+)
+
+// MapToItem 字段名拼写错误
+//
+//map2struct:field Titel=heading
+//map2struct:ignore Lang Langs
+func MapToItem(src map[string]string) (*model.ApiItemInfo, error) {
+	return nil, nil
+}
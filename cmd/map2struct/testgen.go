@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/adyzng/gotool/parse"
+	"github.com/adyzng/gotool/tpl"
+	"github.com/adyzng/gotool/utils"
+)
+
+// setTestValues 根据字段类型生成单测用例：正常值、错误类型、边界值
+// baseType 为字段（或枚举）的基础类型，valueType 为 map 的 value 类型
+func setTestValues(fd *tpl.FieldItem, baseType, valueType string, strict bool) {
+	typeExpr := baseType
+	if fd.TypeConv != "" {
+		typeExpr = fd.TypeConv
+	}
+
+	var valid, wrong string
+	switch {
+	case baseType == "string":
+		valid = "abc"
+	case baseType == "bool":
+		valid, wrong = "true", "maybe"
+	case strings.HasPrefix(baseType, "float"):
+		valid, wrong = "1.5", "not-a-number"
+	case utils.IsNumberType(baseType):
+		valid, wrong = "42", "not-a-number"
+	default:
+		return
+	}
+
+	expect := fmt.Sprintf("%s(%s)", typeExpr, valid)
+	switch {
+	case baseType == "string" && fd.TypeConv == "":
+		expect = strconv.Quote(valid)
+	case baseType == "string":
+		expect = fmt.Sprintf("%s(%q)", typeExpr, valid)
+	case baseType == "bool" && fd.TypeConv == "":
+		expect = valid
+	}
+	if fd.TestValue = srcLiteral(valid, baseType, valueType, false); fd.TestValue == "" {
+		return
+	}
+	fd.TestCheck = fieldCheck(fd, expect)
+
+	if wrong != "" {
+		if lit := srcLiteral(wrong, "string", valueType, false); lit != "" {
+			fd.TestCases = append(fd.TestCases, &tpl.TestCase{
+				Name:    "wrong_type/" + fd.JsonName,
+				Value:   lit,
+				WantErr: strict,
+			})
+		}
+	}
+
+	// 整数的边界值和溢出
+	min, max, overflow := intBounds(baseType)
+	if max == nil {
+		return
+	}
+	for _, bd := range []struct {
+		name string
+		val  *big.Int
+	}{{"min", min}, {"max", max}} {
+		lit := srcLiteral(bd.val.String(), baseType, valueType, true)
+		if lit == "" {
+			continue
+		}
+		fd.TestCases = append(fd.TestCases, &tpl.TestCase{
+			Name:  bd.name + "/" + fd.JsonName,
+			Value: lit,
+			Check: fieldCheck(fd, fmt.Sprintf("%s(%s)", typeExpr, bd.val)),
+		})
+	}
+	if overflow != nil {
+		if lit := srcLiteral(overflow.String(), baseType, valueType, true); lit != "" {
+			fd.TestCases = append(fd.TestCases, &tpl.TestCase{
+				Name:    "overflow/" + fd.JsonName,
+				Value:   lit,
+				WantErr: strict,
+			})
+		}
+	}
+
+	// json.Unmarshal 得到的 float64: 小数和超过 2^53 的大数都会丢失精度
+	if valueType == "interface{}" {
+		fd.TestCases = append(fd.TestCases, &tpl.TestCase{
+			Name:    "fraction/" + fd.JsonName,
+			Value:   "float64(1.5)",
+			WantErr: strict,
+		})
+		if baseType == "int64" || baseType == "uint64" {
+			fd.TestCases = append(fd.TestCases, &tpl.TestCase{
+				Name:    "lossy/" + fd.JsonName,
+				Value:   "float64(1 << 60)",
+				WantErr: strict,
+			})
+		}
+	}
+}
+
+// srcLiteral 生成 map value 的字面量，不支持的组合返回空
+func srcLiteral(text, baseType, valueType string, exact bool) string {
+	switch valueType {
+	case "string":
+		return strconv.Quote(text)
+	case "interface{}":
+		switch {
+		case baseType == "string":
+			return strconv.Quote(text)
+		case baseType == "bool":
+			return text
+		case exact:
+			return fmt.Sprintf("json.Number(%q)", text)
+		default:
+			return fmt.Sprintf("float64(%s)", text)
+		}
+	}
+	if baseType == valueType {
+		return fmt.Sprintf("%s(%s)", valueType, text)
+	}
+	return ""
+}
+
+// wrapMultiValues 多值 map 的单测取值包装为切片
+func wrapMultiValues(fd *tpl.FieldItem, input *parse.MapType) {
+	if !input.Multi {
+		return
+	}
+	wrap := func(lit string) string {
+		if lit == "" {
+			return ""
+		}
+		return fmt.Sprintf("[]%s{%s}", input.ValueType, lit)
+	}
+	fd.TestValue = wrap(fd.TestValue)
+	for _, tc := range fd.TestCases {
+		tc.Value = wrap(tc.Value)
+	}
+}
+
+func fieldCheck(fd *tpl.FieldItem, expect string) string {
+	if fd.GenType == "slice" {
+		return fmt.Sprintf(
+			"if len(obj.%s) != 1 || obj.%s[0] != %s {\n\tt.Errorf(\"%s = %%v, want [%%v]\", obj.%s, %s)\n}",
+			fd.FieldName, fd.FieldName, expect, fd.FieldName, fd.FieldName, expect,
+		)
+	}
+	if fd.IsPointer {
+		return fmt.Sprintf(
+			"if obj.%s == nil || *obj.%s != %s {\n\tt.Errorf(\"%s = %%v, want %%v\", obj.%s, %s)\n}",
+			fd.FieldName, fd.FieldName, expect, fd.FieldName, fd.FieldName, expect,
+		)
+	}
+	return fmt.Sprintf(
+		"if obj.%s != %s {\n\tt.Errorf(\"%s = %%v, want %%v\", obj.%s, %s)\n}",
+		fd.FieldName, expect, fd.FieldName, fd.FieldName, expect,
+	)
+}
+
+// intBounds 整数类型的最小值、最大值和溢出值；int/uint 与平台相关，只测试 32 位范围且不测溢出
+func intBounds(typ string) (min, max, overflow *big.Int) {
+	bits := 0
+	signed := strings.HasPrefix(typ, "int")
+	switch typ {
+	case "int8", "uint8":
+		bits = 8
+	case "int16", "uint16":
+		bits = 16
+	case "int32", "uint32", "int", "uint":
+		bits = 32
+	case "int64", "uint64":
+		bits = 64
+	default:
+		return nil, nil, nil
+	}
+
+	one := big.NewInt(1)
+	if signed {
+		max = new(big.Int).Sub(new(big.Int).Lsh(one, uint(bits-1)), one)
+		min = new(big.Int).Neg(new(big.Int).Lsh(one, uint(bits-1)))
+	} else {
+		max = new(big.Int).Sub(new(big.Int).Lsh(one, uint(bits)), one)
+		min = big.NewInt(0)
+	}
+	if typ != "int" && typ != "uint" {
+		overflow = new(big.Int).Add(max, one)
+	}
+	return min, max, overflow
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/adyzng/gotool/parse"
+)
+
+// 不需要函数声明，直接根据 -type 指定的类型生成转换函数: genMapTo<Type>
+var typeSources = map[string]*parse.MapType{
+	"string":    {KeyType: "string", ValueType: "string"},
+	"interface": {KeyType: "string", ValueType: "interface{}", IsValueInterface: true},
+}
+
+// findTypeFuncList 根据 -type/-pkg/-src 构造转换函数，返回需要额外 import 的包
+func findTypeFuncList(pkg *parse.PackageV2, types, pkgPath, src string) ([]*parse.FunctionV2, []string, error) {
+	if types == "" {
+		return nil, nil, nil
+	}
+	if pkgPath == "" {
+		return nil, nil, fmt.Errorf("-pkg is required with -type. type=%s", types)
+	}
+	input, ok := typeSources[src]
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid param. src=%s", src)
+	}
+
+	var list []*parse.FunctionV2
+	for _, name := range strings.Split(types, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		fun, err := pkg.NewTypeFunc(*funcPrefix+name, input, pkgPath, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		list = append(list, fun)
+	}
+	return list, []string{pkgPath}, nil
+}
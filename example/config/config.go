@@ -0,0 +1,24 @@
+package config
+
+import (
+	"github.com/adyzng/gotool/example/model"
+)
+
+//go:generate map2struct -strict -register
+
+// MapToServerConfig 使用 model.NewServerConfig() 创建对象
+func MapToServerConfig(src map[string]string) (*model.ServerConfig, error) {
+	return nil, nil
+}
+
+// MapToLocalServerConfig 通过指令指定构造函数
+//
+//map2struct:new NewLocalServerConfig
+func MapToLocalServerConfig(src map[string]string) (*model.ServerConfig, error) {
+	return nil, nil
+}
+
+// MapToCacheConfig 创建对象后调用 Default()
+func MapToCacheConfig(src map[string]string) (*model.CacheConfig, error) {
+	return nil, nil
+}
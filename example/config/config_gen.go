@@ -0,0 +1,135 @@
+// Auto generated code, DO NOT EDIT.
+
+package config
+
+import (
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+)
+
+func genMapToCacheConfig(src map[string]string) (obj *model.CacheConfig, err error) {
+	obj = &model.CacheConfig{}
+	obj.Default()
+	if err = genApplyCacheConfig(src, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// genApplyCacheConfig 只覆盖 src 中存在的字段
+func genApplyCacheConfig(src map[string]string, obj *model.CacheConfig) (err error) {
+	// 直接赋值的字段
+	if tmp, ok := src["size"]; ok {
+		if obj.Size, err = m2s.ToIntE(tmp); err != nil {
+			return &m2s.KeyError{Key: "size", Err: err}
+		}
+	}
+	if tmp, ok := src["ttl"]; ok {
+		if obj.TTLSeconds, err = m2s.ToInt64E(tmp); err != nil {
+			return &m2s.KeyError{Key: "ttl", Err: err}
+		}
+	}
+	if tmp, ok := src["policy"]; ok {
+		obj.Policy = tmp
+	}
+
+	return err
+}
+
+func init() {
+	m2s.Register((map[string]string)(nil), (*model.CacheConfig)(nil), &m2s.Converter{
+		MapTo: func(src interface{}) (interface{}, error) {
+			obj, err := genMapToCacheConfig(src.(map[string]string))
+			if err != nil {
+				return nil, err
+			}
+			return obj, nil
+		},
+		Apply: func(src interface{}, obj interface{}) error {
+			err := genApplyCacheConfig(src.(map[string]string), obj.(*model.CacheConfig))
+			return err
+		},
+	})
+}
+
+func genMapToLocalServerConfig(src map[string]string) (obj *model.ServerConfig, err error) {
+	obj = model.NewLocalServerConfig()
+	if err = genApplyLocalServerConfig(src, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// genApplyLocalServerConfig 只覆盖 src 中存在的字段
+func genApplyLocalServerConfig(src map[string]string, obj *model.ServerConfig) (err error) {
+	// 直接赋值的字段
+	if tmp, ok := src["host"]; ok {
+		obj.Host = tmp
+	}
+	if tmp, ok := src["port"]; ok {
+		if obj.Port, err = m2s.ToIntE(tmp); err != nil {
+			return &m2s.KeyError{Key: "port", Err: err}
+		}
+	}
+	if tmp, ok := src["timeout_ms"]; ok {
+		if obj.TimeoutMs, err = m2s.ToInt64E(tmp); err != nil {
+			return &m2s.KeyError{Key: "timeout_ms", Err: err}
+		}
+	}
+	if tmp, ok := src["Debug"]; ok {
+		if obj.Debug, err = m2s.ToBoolE(tmp); err != nil {
+			return &m2s.KeyError{Key: "Debug", Err: err}
+		}
+	}
+
+	return err
+}
+
+func init() {
+	m2s.Register((map[string]string)(nil), (*model.ServerConfig)(nil), &m2s.Converter{
+		MapTo: func(src interface{}) (interface{}, error) {
+			obj, err := genMapToLocalServerConfig(src.(map[string]string))
+			if err != nil {
+				return nil, err
+			}
+			return obj, nil
+		},
+		Apply: func(src interface{}, obj interface{}) error {
+			err := genApplyLocalServerConfig(src.(map[string]string), obj.(*model.ServerConfig))
+			return err
+		},
+	})
+}
+
+func genMapToServerConfig(src map[string]string) (obj *model.ServerConfig, err error) {
+	obj = model.NewServerConfig()
+	if err = genApplyServerConfig(src, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// genApplyServerConfig 只覆盖 src 中存在的字段
+func genApplyServerConfig(src map[string]string, obj *model.ServerConfig) (err error) {
+	// 直接赋值的字段
+	if tmp, ok := src["host"]; ok {
+		obj.Host = tmp
+	}
+	if tmp, ok := src["port"]; ok {
+		if obj.Port, err = m2s.ToIntE(tmp); err != nil {
+			return &m2s.KeyError{Key: "port", Err: err}
+		}
+	}
+	if tmp, ok := src["timeout_ms"]; ok {
+		if obj.TimeoutMs, err = m2s.ToInt64E(tmp); err != nil {
+			return &m2s.KeyError{Key: "timeout_ms", Err: err}
+		}
+	}
+	if tmp, ok := src["Debug"]; ok {
+		if obj.Debug, err = m2s.ToBoolE(tmp); err != nil {
+			return &m2s.KeyError{Key: "Debug", Err: err}
+		}
+	}
+
+	return err
+}
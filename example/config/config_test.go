@@ -0,0 +1,61 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+)
+
+func TestMapToServerConfig(t *testing.T) {
+	obj, err := genMapToServerConfig(map[string]string{"port": "9090"})
+	if err != nil {
+		t.Fatalf("convert failed. err=%v", err)
+	}
+	// 缺失的 key 保留 NewServerConfig 的默认值
+	want := model.NewServerConfig()
+	want.Port = 9090
+	if *obj != *want {
+		t.Errorf("got %+v, want %+v", obj, want)
+	}
+
+	obj, err = genMapToLocalServerConfig(map[string]string{"timeout_ms": "100"})
+	if err != nil {
+		t.Fatalf("convert failed. err=%v", err)
+	}
+	want = model.NewLocalServerConfig()
+	want.TimeoutMs = 100
+	if *obj != *want {
+		t.Errorf("map2struct:new ignored. got %+v, want %+v", obj, want)
+	}
+
+	if _, err = genMapToServerConfig(map[string]string{"port": "99999999999999999999"}); err == nil {
+		t.Errorf("strict mode should reject int overflow")
+	}
+}
+
+func TestMapToCacheConfig(t *testing.T) {
+	obj, err := genMapToCacheConfig(map[string]string{"policy": "fifo"})
+	if err != nil {
+		t.Fatalf("convert failed. err=%v", err)
+	}
+	if obj.Size != 1024 || obj.TTLSeconds != 60 || obj.Policy != "fifo" {
+		t.Errorf("Default() not applied. obj=%+v", obj)
+	}
+
+	// genApply 不调用 Default()，只覆盖 src 中存在的字段
+	cfg := &model.CacheConfig{Size: 1}
+	if err = genApplyCacheConfig(map[string]string{"ttl": "5"}, cfg); err != nil || cfg.Size != 1 || cfg.TTLSeconds != 5 || cfg.Policy != "" {
+		t.Errorf("unexpected apply. cfg=%+v err=%v", cfg, err)
+	}
+}
+
+func TestDecodeRegisteredConfig(t *testing.T) {
+	obj, err := m2s.DecodeNew(map[string]string{"size": "8"}, (*model.CacheConfig)(nil))
+	if err != nil {
+		t.Fatalf("decode new failed. err=%v", err)
+	}
+	if cfg := obj.(*model.CacheConfig); cfg.Size != 8 || cfg.Policy != "lru" {
+		t.Errorf("registered converter not used. cfg=%+v", cfg)
+	}
+}
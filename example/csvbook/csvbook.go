@@ -0,0 +1,17 @@
+package csvbook
+
+import (
+	"github.com/adyzng/gotool/example/model"
+)
+
+//go:generate map2struct -strict -csv
+
+// MapToBookRow CSV 中的一行
+func MapToBookRow(src map[string]string) (*model.ApiBookInfo, error) {
+	return nil, nil
+}
+
+// MapToTaggedBook 列表字段写入 CSV 时使用相同的分隔符拼接
+func MapToTaggedBook(src map[string]string) (*model.TaggedBook, error) {
+	return nil, nil
+}
@@ -0,0 +1,346 @@
+// Auto generated code, DO NOT EDIT.
+
+package csvbook
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+)
+
+func genMapToBookRow(src map[string]string) (obj *model.ApiBookInfo, err error) {
+	obj = &model.ApiBookInfo{}
+	if err = genApplyBookRow(src, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// genApplyBookRow 只覆盖 src 中存在的字段
+func genApplyBookRow(src map[string]string, obj *model.ApiBookInfo) (err error) {
+	// 直接赋值的字段
+	if tmp, ok := src["book_id"]; ok {
+		if obj.Id, err = m2s.ToInt64E(tmp); err != nil {
+			return &m2s.KeyError{Key: "book_id", Err: err}
+		}
+	}
+	if tmp, ok := src["book_name"]; ok {
+		obj.Name = tmp
+	}
+	if tmp, ok := src["copyright_info"]; ok {
+		obj.CopyrightInfo = tmp
+	}
+	if tmp, ok := src["create_time"]; ok {
+		obj.CreateTime = tmp
+	}
+	if tmp, ok := src["thumb_url"]; ok {
+		obj.ThumbUrl = tmp
+	}
+	if tmp, ok := src["is_first_read"]; ok {
+		if obj.IsFirstRead, err = m2s.ToBoolE(tmp); err != nil {
+			return &m2s.KeyError{Key: "is_first_read", Err: err}
+		}
+	}
+
+	// 枚举类型
+	if tmp, ok := src["book_type"]; ok {
+		num, err := m2s.ToInt64E(tmp)
+		if err != nil {
+			return &m2s.KeyError{Key: "book_type", Err: err}
+		}
+		val := (model.BookType)(num)
+		obj.BookType = &val
+	}
+
+	// 带赋值表达式的（指针类型）
+	if tmp, ok := src["serial_count"]; ok {
+		val, err := m2s.ToInt32E(tmp)
+		if err != nil {
+			return &m2s.KeyError{Key: "serial_count", Err: err}
+		}
+		obj.SerialCount = &val
+	}
+	if tmp, ok := src["latest_read_time"]; ok {
+		val, err := m2s.ToInt64E(tmp)
+		if err != nil {
+			return &m2s.KeyError{Key: "latest_read_time", Err: err}
+		}
+		obj.LatestReadTime = &val
+	}
+	if tmp, ok := src["category"]; ok {
+		val := tmp
+		obj.Category = &val
+	}
+
+	return err
+}
+
+// genBookRowCSVReader 逐行读取 CSV，第一行为表头（map 的 key），空值视为不存在
+type genBookRowCSVReader struct {
+	r      *csv.Reader
+	header []string
+	src    map[string]string
+	record int
+}
+
+// genNewBookRowCSVReader 读取表头，返回的 reader 不会把整个文件读入内存
+func genNewBookRowCSVReader(r io.Reader) (*genBookRowCSVReader, error) {
+	cr := csv.NewReader(r)
+	cr.ReuseRecord = true
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	return &genBookRowCSVReader{
+		r:      cr,
+		header: append([]string(nil), header...),
+		src:    make(map[string]string, len(header)),
+		record: 1,
+	}, nil
+}
+
+// Read 读取下一条记录，读完返回 io.EOF；转换失败返回 *m2s.CSVError
+func (r *genBookRowCSVReader) Read() (*model.ApiBookInfo, error) {
+	record, err := r.r.Read()
+	if err != nil {
+		return nil, err
+	}
+	r.record++
+	for idx, key := range r.header {
+		if record[idx] == "" {
+			delete(r.src, key)
+		} else {
+			r.src[key] = record[idx]
+		}
+	}
+	obj, err := genMapToBookRow(r.src)
+	if err != nil {
+		return nil, m2s.NewCSVError(r.record, r.header, err)
+	}
+	return obj, nil
+}
+
+// genBookRowCSVWriter 写入表头和记录
+type genBookRowCSVWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+// genBookRowCSVHeader writer 写入的表头
+var genBookRowCSVHeader = []string{
+	"book_id",
+	"book_name",
+	"copyright_info",
+	"create_time",
+	"serial_count",
+	"thumb_url",
+	"book_type",
+	"latest_read_time",
+	"category",
+	"is_first_read",
+}
+
+// genNewBookRowCSVWriter 写入表头
+func genNewBookRowCSVWriter(w io.Writer) (*genBookRowCSVWriter, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(genBookRowCSVHeader); err != nil {
+		return nil, err
+	}
+	return &genBookRowCSVWriter{w: cw, record: make([]string, len(genBookRowCSVHeader))}, nil
+}
+
+// Write 写入一条记录，nil 指针写入空值
+func (w *genBookRowCSVWriter) Write(obj *model.ApiBookInfo) error {
+	w.record[0] = fmt.Sprint(obj.Id)
+	w.record[1] = obj.Name
+	w.record[2] = obj.CopyrightInfo
+	w.record[3] = obj.CreateTime
+	w.record[4] = ""
+	if obj.SerialCount != nil {
+		w.record[4] = fmt.Sprint(*obj.SerialCount)
+	}
+	w.record[5] = obj.ThumbUrl
+	w.record[6] = ""
+	if obj.BookType != nil {
+		w.record[6] = fmt.Sprint(int64(*obj.BookType))
+	}
+	w.record[7] = ""
+	if obj.LatestReadTime != nil {
+		w.record[7] = fmt.Sprint(*obj.LatestReadTime)
+	}
+	w.record[8] = ""
+	if obj.Category != nil {
+		w.record[8] = fmt.Sprint(*obj.Category)
+	}
+	w.record[9] = fmt.Sprint(obj.IsFirstRead)
+	return w.w.Write(w.record)
+}
+
+// Flush 写入缓冲的数据
+func (w *genBookRowCSVWriter) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+func genMapToTaggedBook(src map[string]string) (obj *model.TaggedBook, err error) {
+	obj = &model.TaggedBook{}
+	if err = genApplyTaggedBook(src, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// genApplyTaggedBook 只覆盖 src 中存在的字段
+func genApplyTaggedBook(src map[string]string, obj *model.TaggedBook) (err error) {
+	// 直接赋值的字段
+	if tmp, ok := src["id"]; ok {
+		if obj.Id, err = m2s.ToInt64E(tmp); err != nil {
+			return &m2s.KeyError{Key: "id", Err: err}
+		}
+	}
+
+	// 切片字段，取所有的值或者按分隔符拆分
+	if tmp, ok := src["author_ids"]; ok {
+		items, err := m2s.SplitE(tmp, ",")
+		if err != nil {
+			return &m2s.KeyError{Key: "author_ids", Err: err}
+		}
+		val := make([]int64, 0, len(items))
+		for idx, item := range items {
+			v, err := m2s.ToInt64E(item)
+			if err != nil {
+				return &m2s.KeyError{Key: "author_ids", Err: &m2s.ElemError{Index: idx, Err: err}}
+			}
+			val = append(val, v)
+		}
+		obj.AuthorIds = val
+	}
+	if tmp, ok := src["tags"]; ok {
+		items, err := m2s.SplitE(tmp, "|")
+		if err != nil {
+			return &m2s.KeyError{Key: "tags", Err: err}
+		}
+		val := make([]string, 0, len(items))
+		for _, item := range items {
+			val = append(val, item)
+		}
+		obj.Tags = val
+	}
+	if tmp, ok := src["types"]; ok {
+		items, err := m2s.SplitE(tmp, ";")
+		if err != nil {
+			return &m2s.KeyError{Key: "types", Err: err}
+		}
+		val := make([]model.BookType, 0, len(items))
+		for idx, item := range items {
+			v, err := m2s.ToInt64E(item)
+			if err != nil {
+				return &m2s.KeyError{Key: "types", Err: &m2s.ElemError{Index: idx, Err: err}}
+			}
+			val = append(val, model.BookType(v))
+		}
+		obj.Types = val
+	}
+
+	return err
+}
+
+// genTaggedBookCSVReader 逐行读取 CSV，第一行为表头（map 的 key），空值视为不存在
+type genTaggedBookCSVReader struct {
+	r      *csv.Reader
+	header []string
+	src    map[string]string
+	record int
+}
+
+// genNewTaggedBookCSVReader 读取表头，返回的 reader 不会把整个文件读入内存
+func genNewTaggedBookCSVReader(r io.Reader) (*genTaggedBookCSVReader, error) {
+	cr := csv.NewReader(r)
+	cr.ReuseRecord = true
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	return &genTaggedBookCSVReader{
+		r:      cr,
+		header: append([]string(nil), header...),
+		src:    make(map[string]string, len(header)),
+		record: 1,
+	}, nil
+}
+
+// Read 读取下一条记录，读完返回 io.EOF；转换失败返回 *m2s.CSVError
+func (r *genTaggedBookCSVReader) Read() (*model.TaggedBook, error) {
+	record, err := r.r.Read()
+	if err != nil {
+		return nil, err
+	}
+	r.record++
+	for idx, key := range r.header {
+		if record[idx] == "" {
+			delete(r.src, key)
+		} else {
+			r.src[key] = record[idx]
+		}
+	}
+	obj, err := genMapToTaggedBook(r.src)
+	if err != nil {
+		return nil, m2s.NewCSVError(r.record, r.header, err)
+	}
+	return obj, nil
+}
+
+// genTaggedBookCSVWriter 写入表头和记录
+type genTaggedBookCSVWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+// genTaggedBookCSVHeader writer 写入的表头
+var genTaggedBookCSVHeader = []string{
+	"id",
+	"author_ids",
+	"tags",
+	"types",
+}
+
+// genNewTaggedBookCSVWriter 写入表头
+func genNewTaggedBookCSVWriter(w io.Writer) (*genTaggedBookCSVWriter, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(genTaggedBookCSVHeader); err != nil {
+		return nil, err
+	}
+	return &genTaggedBookCSVWriter{w: cw, record: make([]string, len(genTaggedBookCSVHeader))}, nil
+}
+
+// Write 写入一条记录，nil 指针写入空值
+func (w *genTaggedBookCSVWriter) Write(obj *model.TaggedBook) error {
+	var parts []string
+	w.record[0] = fmt.Sprint(obj.Id)
+	parts = parts[:0]
+	for _, v := range obj.AuthorIds {
+		parts = append(parts, fmt.Sprint(v))
+	}
+	w.record[1] = strings.Join(parts, ",")
+	parts = parts[:0]
+	for _, v := range obj.Tags {
+		parts = append(parts, v)
+	}
+	w.record[2] = strings.Join(parts, "|")
+	parts = parts[:0]
+	for _, v := range obj.Types {
+		parts = append(parts, fmt.Sprint(int64(v)))
+	}
+	w.record[3] = strings.Join(parts, ";")
+	return w.w.Write(w.record)
+}
+
+// Flush 写入缓冲的数据
+func (w *genTaggedBookCSVWriter) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
@@ -0,0 +1,127 @@
+package csvbook
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+)
+
+func TestBookCSVRoundTrip(t *testing.T) {
+	count, bookType := int32(3), model.BookType(2)
+	books := []*model.ApiBookInfo{
+		{Id: 1, Name: "go, \"the\" book", SerialCount: &count, BookType: &bookType, IsFirstRead: true},
+		{Id: 2, Name: "rust"},
+	}
+
+	buf := &bytes.Buffer{}
+	w, err := genNewBookRowCSVWriter(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, book := range books {
+		if err = w.Write(book); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := genNewBookRowCSVReader(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []*model.ApiBookInfo
+	for {
+		book, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("read failed. err=%v", err)
+		}
+		got = append(got, book)
+	}
+	if !reflect.DeepEqual(got, books) {
+		t.Errorf("got = %+v, want %+v", got, books)
+	}
+}
+
+func TestBookCSVError(t *testing.T) {
+	// 第 2 条记录的字段包含换行，出错的是第 3 条记录、文件的第 4 行
+	data := "book_name,book_id\n\"go\nlang\",1\nrust,abc\n"
+	r, err := genNewBookRowCSVReader(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = r.Read(); err != nil {
+		t.Fatalf("read failed. err=%v", err)
+	}
+
+	_, err = r.Read()
+	var csvErr *m2s.CSVError
+	if !errors.As(err, &csvErr) || csvErr.Record != 3 || csvErr.Column != 2 {
+		t.Errorf("want csv error at record 3 column 2. err=%v", err)
+	}
+	if _, err = r.Read(); err != io.EOF {
+		t.Errorf("want EOF. err=%v", err)
+	}
+}
+
+func TestTaggedBookCSV(t *testing.T) {
+	books := []*model.TaggedBook{
+		{Id: 1, AuthorIds: []int64{7, 8}, Tags: []string{"go", "lang"}, Types: []model.BookType{1, 3}},
+		{Id: 2},
+	}
+	buf := &bytes.Buffer{}
+	w, err := genNewTaggedBookCSVWriter(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, book := range books {
+		if err = w.Write(book); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	// BookType 实现了 String()，写入的仍然是数字，才能读回
+	if !strings.Contains(buf.String(), `1,"7,8",go|lang,1;3`) {
+		t.Errorf("unexpected csv. data=%s", buf.String())
+	}
+
+	r, err := genNewTaggedBookCSVReader(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range books {
+		got, err := r.Read()
+		if err != nil {
+			t.Fatalf("read failed. err=%v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got = %+v, want %+v", got, want)
+		}
+	}
+}
+
+func TestTaggedBookSepError(t *testing.T) {
+	obj, err := genMapToTaggedBook(map[string]string{"author_ids": " 1, 2 ,3", "tags": ""})
+	if err != nil || !reflect.DeepEqual(obj.AuthorIds, []int64{1, 2, 3}) || len(obj.Tags) != 0 {
+		t.Errorf("split failed. obj=%+v err=%v", obj, err)
+	}
+
+	var ke *m2s.KeyError
+	var ee *m2s.ElemError
+	_, err = genMapToTaggedBook(map[string]string{"author_ids": "1,x"})
+	if !errors.As(err, &ke) || ke.Key != "author_ids" || !errors.As(err, &ee) || ee.Index != 1 {
+		t.Errorf("invalid element should fail. err=%v", err)
+	}
+}
@@ -0,0 +1,25 @@
+package directive
+
+import (
+	"github.com/adyzng/gotool/example/model"
+)
+
+//go:generate map2struct -strict -unknown=return
+
+// MapToVendorBook 不能修改模型的 tag 时，通过指令指定 key: book_id, book_name, book_create_time ...
+//
+//map2struct:prefix book_
+//map2struct:field Id=id Name=name
+//map2struct:field BookType=type
+//map2struct:ignore CopyrightInfo ThumbUrl
+func MapToVendorBook(src map[string]string) (*model.ApiBookInfo, error) {
+	return nil, nil
+}
+
+// MapToThriftItem 只对这个函数使用 thrift tag
+//
+//map2struct:tag thrift
+//map2struct:field Title=name
+func MapToThriftItem(src map[string]interface{}) (*model.ApiItemInfo, error) {
+	return nil, nil
+}
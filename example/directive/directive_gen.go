@@ -0,0 +1,147 @@
+// Auto generated code, DO NOT EDIT.
+
+package directive
+
+import (
+	"sort"
+
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+	"github.com/spf13/cast"
+)
+
+func genMapToThriftItem(src map[string]interface{}) (obj *model.ApiItemInfo, unknown []string, err error) {
+	if _, ok := src["item_id"]; !ok {
+		return nil, unknown, &m2s.KeyError{Key: "item_id", Err: m2s.ErrMissingKey}
+	}
+	if _, ok := src["name"]; !ok {
+		return nil, unknown, &m2s.KeyError{Key: "name", Err: m2s.ErrMissingKey}
+	}
+	obj = model.NewApiItemInfo()
+	if unknown, err = genApplyThriftItem(src, obj); err != nil {
+		return nil, unknown, err
+	}
+	return obj, unknown, nil
+}
+
+// genApplyThriftItem 只覆盖 src 中存在的字段
+func genApplyThriftItem(src map[string]interface{}, obj *model.ApiItemInfo) (unknown []string, err error) {
+	// 检查未知的 key
+	for key := range src {
+		switch key {
+		case "item_id", "name", "status", "score", "lang":
+		default:
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+
+	// 直接赋值的字段
+	if tmp, ok := src["item_id"]; ok {
+		if obj.ItemId, err = m2s.ToInt64E(tmp); err != nil {
+			return unknown, &m2s.KeyError{Key: "item_id", Err: err}
+		}
+	}
+	if tmp, ok := src["name"]; ok {
+		if obj.Title, err = cast.ToStringE(tmp); err != nil {
+			return unknown, &m2s.KeyError{Key: "name", Err: err}
+		}
+	}
+	if tmp, ok := src["lang"]; ok {
+		if obj.Lang, err = cast.ToStringE(tmp); err != nil {
+			return unknown, &m2s.KeyError{Key: "lang", Err: err}
+		}
+	}
+
+	// 枚举类型
+	if tmp, ok := src["status"]; ok {
+		num, err := m2s.ToInt64E(tmp)
+		if err != nil {
+			return unknown, &m2s.KeyError{Key: "status", Err: err}
+		}
+		val := (model.ItemStatus)(num)
+		obj.Status = val
+	}
+
+	// 带赋值表达式的（指针类型）
+	if tmp, ok := src["score"]; ok {
+		val, err := m2s.ToFloat64E(tmp)
+		if err != nil {
+			return unknown, &m2s.KeyError{Key: "score", Err: err}
+		}
+		obj.Score = &val
+	}
+
+	return unknown, err
+}
+
+func genMapToVendorBook(src map[string]string) (obj *model.ApiBookInfo, unknown []string, err error) {
+	obj = &model.ApiBookInfo{}
+	if unknown, err = genApplyVendorBook(src, obj); err != nil {
+		return nil, unknown, err
+	}
+	return obj, unknown, nil
+}
+
+// genApplyVendorBook 只覆盖 src 中存在的字段
+func genApplyVendorBook(src map[string]string, obj *model.ApiBookInfo) (unknown []string, err error) {
+	// 检查未知的 key
+	for key := range src {
+		switch key {
+		case "book_id", "book_name", "book_create_time", "book_serial_count", "book_type", "book_latest_read_time", "book_category", "book_is_first_read":
+		default:
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+
+	// 直接赋值的字段
+	if tmp, ok := src["book_id"]; ok {
+		if obj.Id, err = m2s.ToInt64E(tmp); err != nil {
+			return unknown, &m2s.KeyError{Key: "book_id", Err: err}
+		}
+	}
+	if tmp, ok := src["book_name"]; ok {
+		obj.Name = tmp
+	}
+	if tmp, ok := src["book_create_time"]; ok {
+		obj.CreateTime = tmp
+	}
+	if tmp, ok := src["book_is_first_read"]; ok {
+		if obj.IsFirstRead, err = m2s.ToBoolE(tmp); err != nil {
+			return unknown, &m2s.KeyError{Key: "book_is_first_read", Err: err}
+		}
+	}
+
+	// 枚举类型
+	if tmp, ok := src["book_type"]; ok {
+		num, err := m2s.ToInt64E(tmp)
+		if err != nil {
+			return unknown, &m2s.KeyError{Key: "book_type", Err: err}
+		}
+		val := (model.BookType)(num)
+		obj.BookType = &val
+	}
+
+	// 带赋值表达式的（指针类型）
+	if tmp, ok := src["book_serial_count"]; ok {
+		val, err := m2s.ToInt32E(tmp)
+		if err != nil {
+			return unknown, &m2s.KeyError{Key: "book_serial_count", Err: err}
+		}
+		obj.SerialCount = &val
+	}
+	if tmp, ok := src["book_latest_read_time"]; ok {
+		val, err := m2s.ToInt64E(tmp)
+		if err != nil {
+			return unknown, &m2s.KeyError{Key: "book_latest_read_time", Err: err}
+		}
+		obj.LatestReadTime = &val
+	}
+	if tmp, ok := src["book_category"]; ok {
+		val := tmp
+		obj.Category = &val
+	}
+
+	return unknown, err
+}
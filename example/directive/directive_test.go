@@ -0,0 +1,43 @@
+package directive
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVendorBookDirectives(t *testing.T) {
+	src := map[string]string{
+		"book_id":             "1",
+		"book_name":           "go",
+		"book_type":           "2",
+		"book_create_time":    "2024",
+		"book_copyright_info": "ignored",
+		"book_thumb_url":      "ignored",
+		"book_book_id":        "x",
+		"id":                  "x",
+	}
+	obj, unknown, err := genMapToVendorBook(src)
+	if err != nil {
+		t.Fatalf("convert failed. err=%v", err)
+	}
+	// field 指令的 key 也加上 prefix，没有指令的字段使用 json tag
+	if obj.Id != 1 || obj.Name != "go" || obj.BookType == nil || *obj.BookType != 2 || obj.CreateTime != "2024" {
+		t.Errorf("unexpected book. obj=%+v", obj)
+	}
+	if obj.CopyrightInfo != "" || obj.ThumbUrl != "" {
+		t.Errorf("ignored fields assigned. obj=%+v", obj)
+	}
+	if want := []string{"book_book_id", "book_copyright_info", "book_thumb_url", "id"}; !reflect.DeepEqual(unknown, want) {
+		t.Errorf("unknown = %v, want %v", unknown, want)
+	}
+}
+
+func TestThriftItemDirectives(t *testing.T) {
+	obj, unknown, err := genMapToThriftItem(map[string]interface{}{"item_id": float64(3), "name": "go", "title": "x"})
+	if err != nil {
+		t.Fatalf("convert failed. err=%v", err)
+	}
+	if obj.ItemId != 3 || obj.Title != "go" || !reflect.DeepEqual(unknown, []string{"title"}) {
+		t.Errorf("unexpected item. obj=%+v unknown=%v", obj, unknown)
+	}
+}
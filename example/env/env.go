@@ -0,0 +1,12 @@
+package env
+
+import (
+	"github.com/adyzng/gotool/example/model"
+)
+
+//go:generate map2struct
+
+// LoadAppConfigFromEnv 从环境变量加载配置，比如 prefix=APP 时读取 APP_NAME, APP_DB_HOST
+func LoadAppConfigFromEnv(prefix string) (*model.AppConfig, error) {
+	return nil, nil
+}
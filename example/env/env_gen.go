@@ -0,0 +1,139 @@
+// Auto generated code, DO NOT EDIT.
+
+package env
+
+import (
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+	"github.com/spf13/cast"
+)
+
+// genAppConfigEnvVars model.AppConfig 读取的环境变量
+func genAppConfigEnvVars(prefix string) []m2s.EnvVar {
+	return []m2s.EnvVar{
+		{Name: m2s.EnvName(prefix, "NAME"), Field: "Name", Type: "string", Required: true},
+		{Name: m2s.EnvName(prefix, "PORT"), Field: "Port", Type: "int32", Default: "8080"},
+		{Name: m2s.EnvName(prefix, "DEBUG"), Field: "Debug", Type: "bool"},
+		{Name: m2s.EnvName(prefix, "LOG_LEVEL"), Field: "LogLevel", Type: "string", Default: "info"},
+		{Name: m2s.EnvName(prefix, "TIMEOUT"), Field: "Timeout", Type: "time.Duration", Default: "3s"},
+		{Name: m2s.EnvName(prefix, "STATUS"), Field: "Status", Type: "model.BookStatus"},
+		{Name: m2s.EnvName(prefix, "BANNER"), Field: "Banner", Type: "string", Default: "hello", AllowEmpty: true},
+		{Name: m2s.EnvName(prefix, "DB_HOST"), Field: "DB.Host", Type: "string", Default: "127.0.0.1"},
+		{Name: m2s.EnvName(prefix, "DB_PORT"), Field: "DB.Port", Type: "int", Default: "3306"},
+		{Name: m2s.EnvName(prefix, "DB_USER"), Field: "DB.User", Type: "string", Required: true},
+		{Name: m2s.EnvName(prefix, "DB_PASSWORD"), Field: "DB.Password", Type: "string"},
+		{Name: m2s.EnvName(prefix, "DB_MAX_CONNS"), Field: "DB.MaxConns", Type: "int32"},
+		{Name: m2s.EnvName(prefix, "CACHE_SIZE"), Field: "Cache.Size", Type: "int"},
+		{Name: m2s.EnvName(prefix, "CACHE_TTL_SECONDS"), Field: "Cache.TTLSeconds", Type: "int64"},
+		{Name: m2s.EnvName(prefix, "CACHE_POLICY"), Field: "Cache.Policy", Type: "string"},
+		{Name: m2s.EnvName(prefix, "SERVER_HOST"), Field: "Server.Host", Type: "string"},
+		{Name: m2s.EnvName(prefix, "SERVER_PORT"), Field: "Server.Port", Type: "int"},
+		{Name: m2s.EnvName(prefix, "SERVER_TIMEOUT_MS"), Field: "Server.TimeoutMs", Type: "int64"},
+		{Name: m2s.EnvName(prefix, "SERVER_DEBUG"), Field: "Server.Debug", Type: "bool"},
+	}
+}
+
+func genLoadAppConfigFromEnv(prefix string) (obj *model.AppConfig, err error) {
+	vars := genAppConfigEnvVars(prefix)
+	if err = m2s.CheckEnv(vars); err != nil {
+		return nil, err
+	}
+	obj = &model.AppConfig{}
+	if obj.Cache == nil {
+		obj.Cache = &model.CacheConfig{}
+		obj.Cache.Default()
+	}
+	if obj.Server == nil {
+		obj.Server = model.NewServerConfig()
+	}
+	if tmp, ok := vars[0].Lookup(); ok {
+		obj.Name = tmp
+	}
+	if tmp, ok := vars[1].Lookup(); ok {
+		if obj.Port, err = m2s.ToInt32E(tmp); err != nil {
+			return nil, &m2s.KeyError{Key: vars[1].Name, Err: err}
+		}
+	}
+	if tmp, ok := vars[2].Lookup(); ok {
+		val, err := m2s.ToBoolE(tmp)
+		if err != nil {
+			return nil, &m2s.KeyError{Key: vars[2].Name, Err: err}
+		}
+		obj.Debug = &val
+	}
+	if tmp, ok := vars[3].Lookup(); ok {
+		obj.LogLevel = tmp
+	}
+	if tmp, ok := vars[4].Lookup(); ok {
+		if obj.Timeout, err = cast.ToDurationE(tmp); err != nil {
+			return nil, &m2s.KeyError{Key: vars[4].Name, Err: err}
+		}
+	}
+	if tmp, ok := vars[5].Lookup(); ok {
+		num, err := m2s.ToEnumE(tmp, model.BookStatus_value)
+		if err != nil {
+			return nil, &m2s.KeyError{Key: vars[5].Name, Err: err}
+		}
+		val := (model.BookStatus)(num)
+		obj.Status = val
+	}
+	if tmp, ok := vars[6].Lookup(); ok {
+		obj.Banner = tmp
+	}
+	if tmp, ok := vars[7].Lookup(); ok {
+		obj.DB.Host = tmp
+	}
+	if tmp, ok := vars[8].Lookup(); ok {
+		if obj.DB.Port, err = m2s.ToIntE(tmp); err != nil {
+			return nil, &m2s.KeyError{Key: vars[8].Name, Err: err}
+		}
+	}
+	if tmp, ok := vars[9].Lookup(); ok {
+		obj.DB.User = tmp
+	}
+	if tmp, ok := vars[10].Lookup(); ok {
+		obj.DB.Password = tmp
+	}
+	if tmp, ok := vars[11].Lookup(); ok {
+		val, err := m2s.ToInt32E(tmp)
+		if err != nil {
+			return nil, &m2s.KeyError{Key: vars[11].Name, Err: err}
+		}
+		obj.DB.MaxConns = &val
+	}
+	if tmp, ok := vars[12].Lookup(); ok {
+		if obj.Cache.Size, err = m2s.ToIntE(tmp); err != nil {
+			return nil, &m2s.KeyError{Key: vars[12].Name, Err: err}
+		}
+	}
+	if tmp, ok := vars[13].Lookup(); ok {
+		if obj.Cache.TTLSeconds, err = m2s.ToInt64E(tmp); err != nil {
+			return nil, &m2s.KeyError{Key: vars[13].Name, Err: err}
+		}
+	}
+	if tmp, ok := vars[14].Lookup(); ok {
+		obj.Cache.Policy = tmp
+	}
+	if tmp, ok := vars[15].Lookup(); ok {
+		obj.Server.Host = tmp
+	}
+	if tmp, ok := vars[16].Lookup(); ok {
+		if obj.Server.Port, err = m2s.ToIntE(tmp); err != nil {
+			return nil, &m2s.KeyError{Key: vars[16].Name, Err: err}
+		}
+	}
+	if tmp, ok := vars[17].Lookup(); ok {
+		if obj.Server.TimeoutMs, err = m2s.ToInt64E(tmp); err != nil {
+			return nil, &m2s.KeyError{Key: vars[17].Name, Err: err}
+		}
+	}
+	if tmp, ok := vars[18].Lookup(); ok {
+		if obj.Server.Debug, err = m2s.ToBoolE(tmp); err != nil {
+			return nil, &m2s.KeyError{Key: vars[18].Name, Err: err}
+		}
+	}
+
+	// 需要手动处理的字段
+	// obj.Peers = ? (PEERS)
+	return obj, nil
+}
@@ -0,0 +1,114 @@
+package env
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+)
+
+func setenv(t *testing.T, kv map[string]string) {
+	for key, val := range kv {
+		os.Setenv(key, val)
+	}
+	t.Cleanup(func() {
+		for key := range kv {
+			os.Unsetenv(key)
+		}
+	})
+}
+
+func TestLoadAppConfigFromEnv(t *testing.T) {
+	if _, err := genLoadAppConfigFromEnv("APP"); !errors.Is(err, m2s.ErrMissingKey) {
+		t.Fatalf("want missing required env. err=%v", err)
+	}
+
+	setenv(t, map[string]string{
+		"APP_NAME":          "demo",
+		"APP_DEBUG":         "true",
+		"APP_STATUS":        "BOOK_STATUS_ONLINE",
+		"APP_DB_USER":       "root",
+		"APP_DB_MAX_CONNS":  "16",
+		"APP_CACHE_POLICY":  "fifo",
+		"APP_TIMEOUT":       "5s",
+		"APP_CACHE_UNKNOWN": "ignored",
+	})
+	cfg, err := genLoadAppConfigFromEnv("APP")
+	if err != nil {
+		t.Fatalf("load failed. err=%v", err)
+	}
+	if cfg.Name != "demo" || cfg.Port != 8080 || cfg.Debug == nil || !*cfg.Debug ||
+		cfg.LogLevel != "info" || cfg.Timeout != 5*time.Second || cfg.Status != model.BookStatus_BOOK_STATUS_ONLINE {
+		t.Errorf("cfg = %+v", cfg)
+	}
+	if cfg.DB.Host != "127.0.0.1" || cfg.DB.Port != 3306 || cfg.DB.User != "root" || *cfg.DB.MaxConns != 16 {
+		t.Errorf("cfg.DB = %+v", cfg.DB)
+	}
+	// 嵌套指针结构体的 Default() 和环境变量
+	if cfg.Cache.Size != 1024 || cfg.Cache.Policy != "fifo" {
+		t.Errorf("cfg.Cache = %+v", cfg.Cache)
+	}
+
+	setenv(t, map[string]string{"APP_DB_PORT": "abc"})
+	var keyErr *m2s.KeyError
+	if _, err = genLoadAppConfigFromEnv("APP"); !errors.As(err, &keyErr) || keyErr.Key != "APP_DB_PORT" {
+		t.Errorf("want key error. err=%v", err)
+	}
+}
+
+func TestPrintAppConfigEnvVars(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := m2s.PrintEnvVars(buf, genAppConfigEnvVars("APP")); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"APP_NAME", "APP_DB_HOST", "APP_CACHE_TTL_SECONDS"} {
+		if !strings.Contains(buf.String(), name) {
+			t.Errorf("missing %s in\n%s", name, buf)
+		}
+	}
+	if strings.Contains(buf.String(), "SECRET") {
+		t.Errorf("env:\"-\" should be ignored\n%s", buf)
+	}
+}
+
+func TestLoadAppConfigNested(t *testing.T) {
+	setenv(t, map[string]string{"APP_NAME": "demo", "APP_DB_USER": "root", "APP_SERVER_PORT": "9090"})
+	cfg, err := genLoadAppConfigFromEnv("APP")
+	if err != nil {
+		t.Fatalf("load failed. err=%v", err)
+	}
+	// 嵌套指针结构体使用 NewServerConfig() 创建
+	want := model.NewServerConfig()
+	want.Port = 9090
+	if cfg.Server == nil || *cfg.Server != *want {
+		t.Errorf("cfg.Server = %+v, want %+v", cfg.Server, want)
+	}
+}
+
+func TestLoadAppConfigEmpty(t *testing.T) {
+	setenv(t, map[string]string{"APP_NAME": "demo", "APP_DB_USER": "root", "APP_PORT": "", "APP_LOG_LEVEL": "", "APP_DB_PORT": ""})
+	cfg, err := genLoadAppConfigFromEnv("APP")
+	if err != nil {
+		t.Fatalf("empty env should be treated as unset. err=%v", err)
+	}
+	if cfg.Port != 8080 || cfg.LogLevel != "info" || cfg.DB.Port != 3306 || cfg.Banner != "hello" {
+		t.Errorf("defaults not applied. cfg=%+v db=%+v", cfg, cfg.DB)
+	}
+
+	// 空的 required 变量等同于缺少
+	setenv(t, map[string]string{"APP_NAME": ""})
+	if _, err = genLoadAppConfigFromEnv("APP"); !errors.Is(err, m2s.ErrMissingKey) {
+		t.Errorf("want missing APP_NAME. err=%v", err)
+	}
+
+	// allowempty 保留空字符串
+	setenv(t, map[string]string{"APP_NAME": "demo", "APP_BANNER": ""})
+	if cfg, err = genLoadAppConfigFromEnv("APP"); err != nil || cfg.Banner != "" {
+		t.Errorf("allowempty ignored. cfg=%+v err=%v", cfg, err)
+	}
+}
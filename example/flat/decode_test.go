@@ -0,0 +1,67 @@
+package flat
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+)
+
+// TestDecodeDiff 生成代码与 m2s.Decode 的结果必须一致，包括嵌套结构体、带下标的切片和未知的 key
+func TestDecodeDiff(t *testing.T) {
+	cases := []map[string]string{
+		{},
+		{"id": "1", "title": "go", "author.id": "7", "author.name": "bob", "publisher_city": "bj"},
+		{"chapters.2.title": "three", "chapters.0.pages": "12", "tags.1": "b", "tags.0": "a"},
+		{"labels": `["a","b"]`, "ext": `{"k":"v"}`, "source": `{"id":1}`},
+		{"publisher_name": "x", "chapters.0.xxx": "1", "tags.0.xxx": "1", "other": "1"},
+		{"chapters.1000.title": "x"},
+		{"chapters.0.pages": "abc"},
+		{"labels": "[1"},
+		{"id": "1.5"},
+	}
+	for _, src := range cases {
+		want, _, wantUnknown, wantErr := genMapToFlatBook(src)
+
+		var gotUnknown []string
+		got := &model.FlatBook{}
+		gotErr := m2s.Decode(src, got, m2s.Strict(), m2s.OnUnknown(func(key string) { gotUnknown = append(gotUnknown, key) }))
+		if (wantErr != nil) != (gotErr != nil) {
+			t.Errorf("src=%v gen err=%v, decode err=%v", src, wantErr, gotErr)
+			continue
+		}
+		if wantErr != nil {
+			continue
+		}
+		sort.Strings(gotUnknown)
+		if !reflect.DeepEqual(want, got) || strings.Join(wantUnknown, ",") != strings.Join(gotUnknown, ",") {
+			t.Errorf("src=%v gen=%+v %v, decode=%+v %v", src, want, wantUnknown, got, gotUnknown)
+		}
+	}
+}
+
+// TestDecodeFlattenDiff //map2struct:flatten 对应 m2s.Flatten
+func TestDecodeFlattenDiff(t *testing.T) {
+	cases := []map[string]interface{}{
+		{"title": "go", "pages": float64(12)},
+		{"editor.id": float64(7), "editor.name": "bob"},
+		{"editor.id": "x"},
+		{"editor": "bob"},
+	}
+	for _, src := range cases {
+		want, _, _, wantErr := genMapToFlatChapter(src)
+
+		got := &model.Chapter{}
+		gotErr := m2s.Decode(src, got, m2s.Strict(), m2s.Flatten(""))
+		if (wantErr != nil) != (gotErr != nil) {
+			t.Errorf("src=%v gen err=%v, decode err=%v", src, wantErr, gotErr)
+			continue
+		}
+		if wantErr == nil && !reflect.DeepEqual(want, got) {
+			t.Errorf("src=%v gen=%+v, decode=%+v", src, want, got)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package flat
+
+import (
+	"github.com/adyzng/gotool/example/model"
+)
+
+//go:generate map2struct -strict -presence -unknown=return
+
+// MapToFlatBook 嵌套结构体和切片的 key 前缀由 m2s tag 指定
+func MapToFlatBook(src map[string]string) (*model.FlatBook, error) {
+	return nil, nil
+}
+
+// MapToFlatChapter 所有嵌套结构体和切片都展开，key 使用 "." 连接: editor.name
+//
+//map2struct:flatten
+func MapToFlatChapter(src map[string]interface{}) (*model.Chapter, error) {
+	return nil, nil
+}
+
+// MapToMergedFlatBook 多个 src 合并时，未知 key 去重后返回
+func MapToMergedFlatBook(base, override map[string]string) (*model.FlatBook, error) {
+	return nil, nil
+}
@@ -0,0 +1,728 @@
+// Auto generated code, DO NOT EDIT.
+
+package flat
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+	"github.com/spf13/cast"
+)
+
+// genFlatBookField 标识 model.FlatBook 的字段
+type genFlatBookField uint
+
+const (
+	genFlatBookField_Id genFlatBookField = iota
+	genFlatBookField_Title
+	genFlatBookField_Author_Id
+	genFlatBookField_Author_Name
+	genFlatBookField_Publisher_Name
+	genFlatBookField_Publisher_City
+	genFlatBookField_Chapters
+	genFlatBookField_Tags
+	genFlatBookField_Labels
+	genFlatBookField_Ext
+	genFlatBookField_Source
+)
+
+var genFlatBookFieldNames = [...]string{
+	"Id",
+	"Title",
+	"Author.Id",
+	"Author.Name",
+	"Publisher.Name",
+	"Publisher.City",
+	"Chapters",
+	"Tags",
+	"Labels",
+	"Ext",
+	"Source",
+}
+
+func (f genFlatBookField) String() string {
+	return genFlatBookFieldNames[f]
+}
+
+// genFlatBookFields 记录 src 中存在的字段
+type genFlatBookFields [1]uint64
+
+func (fs *genFlatBookFields) set(f genFlatBookField) {
+	fs[f/64] |= 1 << (f % 64)
+}
+
+// Has 字段是否出现在 src 中
+func (fs genFlatBookFields) Has(f genFlatBookField) bool {
+	return fs[f/64]&(1<<(f%64)) != 0
+}
+
+// Names 出现在 src 中的字段名
+func (fs genFlatBookFields) Names() []string {
+	names := make([]string, 0, len(genFlatBookFieldNames))
+	for idx, name := range genFlatBookFieldNames {
+		if fs.Has(genFlatBookField(idx)) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func genMapToFlatBook(src map[string]string) (obj *model.FlatBook, fields genFlatBookFields, unknown []string, err error) {
+	obj = &model.FlatBook{}
+	if fields, unknown, err = genApplyFlatBook(src, obj); err != nil {
+		return nil, fields, unknown, err
+	}
+	return obj, fields, unknown, nil
+}
+
+// genApplyFlatBook 只覆盖 src 中存在的字段
+func genApplyFlatBook(src map[string]string, obj *model.FlatBook) (fields genFlatBookFields, unknown []string, err error) {
+	// 检查未知的 key
+	for key := range src {
+		switch key {
+		case "id", "title", "author.id", "author.name", "publisher_name", "publisher_city", "labels", "ext", "source":
+		default:
+			if _, ok := m2s.KeyIndex(key, "chapters.", ".", genApplyFlatBookChaptersElemKey); ok {
+				continue
+			}
+			if _, ok := m2s.KeyIndex(key, "tags.", ".", nil); ok {
+				continue
+			}
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+
+	// 直接赋值的字段
+	if tmp, ok := src["id"]; ok {
+		if obj.Id, err = m2s.ToInt64E(tmp); err != nil {
+			return fields, unknown, &m2s.KeyError{Key: "id", Err: err}
+		}
+		fields.set(genFlatBookField_Id)
+	}
+	if tmp, ok := src["title"]; ok {
+		obj.Title = tmp
+		fields.set(genFlatBookField_Title)
+	}
+	if tmp, ok := src["author.id"]; ok {
+		if obj.Author.Id, err = m2s.ToInt64E(tmp); err != nil {
+			return fields, unknown, &m2s.KeyError{Key: "author.id", Err: err}
+		}
+		fields.set(genFlatBookField_Author_Id)
+	}
+	if tmp, ok := src["author.name"]; ok {
+		obj.Author.Name = tmp
+		fields.set(genFlatBookField_Author_Name)
+	}
+	if tmp, ok := src["publisher_name"]; ok {
+		if obj.Publisher == nil {
+			obj.Publisher = &model.Publisher{}
+			obj.Publisher.Default()
+		}
+		obj.Publisher.Name = tmp
+		fields.set(genFlatBookField_Publisher_Name)
+	}
+	if tmp, ok := src["publisher_city"]; ok {
+		if obj.Publisher == nil {
+			obj.Publisher = &model.Publisher{}
+			obj.Publisher.Default()
+		}
+		obj.Publisher.City = tmp
+		fields.set(genFlatBookField_Publisher_City)
+	}
+
+	// 带下标的 key 组成的切片，比如: items.0.title
+	var indexLen [2]int
+	for key := range src {
+		if idx, ok := m2s.KeyIndex(key, "chapters.", ".", genApplyFlatBookChaptersElemKey); ok && idx >= 1000 {
+			return fields, unknown, &m2s.KeyError{Key: key, Err: &m2s.ElemError{Index: idx, Err: m2s.ErrIndexRange}}
+		} else if ok && idx >= indexLen[0] {
+			indexLen[0] = idx + 1
+		}
+		if idx, ok := m2s.KeyIndex(key, "tags.", ".", nil); ok && idx >= 1000 {
+			return fields, unknown, &m2s.KeyError{Key: key, Err: &m2s.ElemError{Index: idx, Err: m2s.ErrIndexRange}}
+		} else if ok && idx >= indexLen[1] {
+			indexLen[1] = idx + 1
+		}
+	}
+	if n := indexLen[0]; n > 0 {
+		// 保留已有的元素，只在下标超出时追加
+		if n > len(obj.Chapters) {
+			obj.Chapters = append(obj.Chapters, make([]*model.Chapter, n-len(obj.Chapters))...)
+		}
+		for i := 0; i < n; i++ {
+			if obj.Chapters[i] == nil {
+				obj.Chapters[i] = &model.Chapter{}
+			}
+			if err = genApplyFlatBookChaptersElem(src, obj.Chapters[i], "chapters."+strconv.Itoa(i)+"."); err != nil {
+				return fields, unknown, err
+			}
+		}
+		fields.set(genFlatBookField_Chapters)
+	}
+	if n := indexLen[1]; n > 0 {
+		// 保留已有的元素，只在下标超出时追加
+		if n > len(obj.Tags) {
+			obj.Tags = append(obj.Tags, make([]string, n-len(obj.Tags))...)
+		}
+		for i := 0; i < n; i++ {
+			key := "tags." + strconv.Itoa(i)
+			if tmp, ok := src[key]; ok {
+				obj.Tags[i] = tmp
+			}
+		}
+		fields.set(genFlatBookField_Tags)
+	}
+
+	// 值为 JSON 字符串的字段
+	if tmp, ok := src["labels"]; ok {
+		if err = m2s.UnmarshalJSON(tmp, &obj.Labels); err != nil {
+			return fields, unknown, &m2s.KeyError{Key: "labels", Err: err}
+		}
+		fields.set(genFlatBookField_Labels)
+	}
+	if tmp, ok := src["ext"]; ok {
+		if err = m2s.UnmarshalJSON(tmp, &obj.Ext); err != nil {
+			return fields, unknown, &m2s.KeyError{Key: "ext", Err: err}
+		}
+		fields.set(genFlatBookField_Ext)
+	}
+	if tmp, ok := src["source"]; ok {
+		if err = m2s.UnmarshalJSON(tmp, &obj.Source); err != nil {
+			return fields, unknown, &m2s.KeyError{Key: "source", Err: err}
+		}
+		fields.set(genFlatBookField_Source)
+	}
+
+	return fields, unknown, err
+}
+
+// genApplyFlatBookChaptersElem 只覆盖 src 中存在的字段
+func genApplyFlatBookChaptersElem(src map[string]string, obj *model.Chapter, prefix string) (err error) {
+	// 直接赋值的字段
+	if tmp, ok := src[prefix+"title"]; ok {
+		obj.Title = tmp
+	}
+	if tmp, ok := src[prefix+"pages"]; ok {
+		if obj.Pages, err = m2s.ToInt32E(tmp); err != nil {
+			return &m2s.KeyError{Key: prefix + "pages", Err: err}
+		}
+	}
+
+	// 需要手动处理的字段
+	// obj.Editor = ?
+
+	return err
+}
+
+// genApplyFlatBookChaptersElemKey 去掉元素前缀后的 key 是否为 model.Chapter 的字段
+func genApplyFlatBookChaptersElemKey(key string) bool {
+	switch key {
+	case "title", "pages", "editor":
+		return true
+	}
+	return false
+}
+
+// genFlatChapterField 标识 model.Chapter 的字段
+type genFlatChapterField uint
+
+const (
+	genFlatChapterField_Title genFlatChapterField = iota
+	genFlatChapterField_Pages
+	genFlatChapterField_Editor_Id
+	genFlatChapterField_Editor_Name
+)
+
+var genFlatChapterFieldNames = [...]string{
+	"Title",
+	"Pages",
+	"Editor.Id",
+	"Editor.Name",
+}
+
+func (f genFlatChapterField) String() string {
+	return genFlatChapterFieldNames[f]
+}
+
+// genFlatChapterFields 记录 src 中存在的字段
+type genFlatChapterFields [1]uint64
+
+func (fs *genFlatChapterFields) set(f genFlatChapterField) {
+	fs[f/64] |= 1 << (f % 64)
+}
+
+// Has 字段是否出现在 src 中
+func (fs genFlatChapterFields) Has(f genFlatChapterField) bool {
+	return fs[f/64]&(1<<(f%64)) != 0
+}
+
+// Names 出现在 src 中的字段名
+func (fs genFlatChapterFields) Names() []string {
+	names := make([]string, 0, len(genFlatChapterFieldNames))
+	for idx, name := range genFlatChapterFieldNames {
+		if fs.Has(genFlatChapterField(idx)) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func genMapToFlatChapter(src map[string]interface{}) (obj *model.Chapter, fields genFlatChapterFields, unknown []string, err error) {
+	obj = &model.Chapter{}
+	if fields, unknown, err = genApplyFlatChapter(src, obj); err != nil {
+		return nil, fields, unknown, err
+	}
+	return obj, fields, unknown, nil
+}
+
+// genApplyFlatChapter 只覆盖 src 中存在的字段
+func genApplyFlatChapter(src map[string]interface{}, obj *model.Chapter) (fields genFlatChapterFields, unknown []string, err error) {
+	// 检查未知的 key
+	for key := range src {
+		switch key {
+		case "title", "pages", "editor.id", "editor.name":
+		default:
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+
+	// 直接赋值的字段
+	if tmp, ok := src["title"]; ok {
+		if obj.Title, err = cast.ToStringE(tmp); err != nil {
+			return fields, unknown, &m2s.KeyError{Key: "title", Err: err}
+		}
+		fields.set(genFlatChapterField_Title)
+	}
+	if tmp, ok := src["pages"]; ok {
+		if obj.Pages, err = m2s.ToInt32E(tmp); err != nil {
+			return fields, unknown, &m2s.KeyError{Key: "pages", Err: err}
+		}
+		fields.set(genFlatChapterField_Pages)
+	}
+	if tmp, ok := src["editor.id"]; ok {
+		if obj.Editor == nil {
+			obj.Editor = &model.Author{}
+		}
+		if obj.Editor.Id, err = m2s.ToInt64E(tmp); err != nil {
+			return fields, unknown, &m2s.KeyError{Key: "editor.id", Err: err}
+		}
+		fields.set(genFlatChapterField_Editor_Id)
+	}
+	if tmp, ok := src["editor.name"]; ok {
+		if obj.Editor == nil {
+			obj.Editor = &model.Author{}
+		}
+		if obj.Editor.Name, err = cast.ToStringE(tmp); err != nil {
+			return fields, unknown, &m2s.KeyError{Key: "editor.name", Err: err}
+		}
+		fields.set(genFlatChapterField_Editor_Name)
+	}
+
+	return fields, unknown, err
+}
+
+// genMergedFlatBookField 标识 model.FlatBook 的字段
+type genMergedFlatBookField uint
+
+const (
+	genMergedFlatBookField_Id genMergedFlatBookField = iota
+	genMergedFlatBookField_Title
+	genMergedFlatBookField_Author_Id
+	genMergedFlatBookField_Author_Name
+	genMergedFlatBookField_Publisher_Name
+	genMergedFlatBookField_Publisher_City
+	genMergedFlatBookField_Chapters
+	genMergedFlatBookField_Tags
+	genMergedFlatBookField_Labels
+	genMergedFlatBookField_Ext
+	genMergedFlatBookField_Source
+)
+
+var genMergedFlatBookFieldNames = [...]string{
+	"Id",
+	"Title",
+	"Author.Id",
+	"Author.Name",
+	"Publisher.Name",
+	"Publisher.City",
+	"Chapters",
+	"Tags",
+	"Labels",
+	"Ext",
+	"Source",
+}
+
+func (f genMergedFlatBookField) String() string {
+	return genMergedFlatBookFieldNames[f]
+}
+
+// genMergedFlatBookFields 记录 base 中存在的字段
+type genMergedFlatBookFields [1]uint64
+
+func (fs *genMergedFlatBookFields) set(f genMergedFlatBookField) {
+	fs[f/64] |= 1 << (f % 64)
+}
+
+// Has 字段是否出现在 base 中
+func (fs genMergedFlatBookFields) Has(f genMergedFlatBookField) bool {
+	return fs[f/64]&(1<<(f%64)) != 0
+}
+
+// Names 出现在 base 中的字段名
+func (fs genMergedFlatBookFields) Names() []string {
+	names := make([]string, 0, len(genMergedFlatBookFieldNames))
+	for idx, name := range genMergedFlatBookFieldNames {
+		if fs.Has(genMergedFlatBookField(idx)) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// genApplyMergedFlatBookFromBase 只覆盖 base 中存在的字段
+func genApplyMergedFlatBookFromBase(base map[string]string, obj *model.FlatBook) (fields genMergedFlatBookFields, unknown []string, err error) {
+	// 检查未知的 key
+	for key := range base {
+		switch key {
+		case "id", "title", "author.id", "author.name", "publisher_name", "publisher_city", "labels", "ext", "source":
+		default:
+			if _, ok := m2s.KeyIndex(key, "chapters.", ".", genApplyMergedFlatBookFromBaseChaptersElemKey); ok {
+				continue
+			}
+			if _, ok := m2s.KeyIndex(key, "tags.", ".", nil); ok {
+				continue
+			}
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+
+	// 直接赋值的字段
+	if tmp, ok := base["id"]; ok {
+		if obj.Id, err = m2s.ToInt64E(tmp); err != nil {
+			return fields, unknown, &m2s.KeyError{Key: "id", Err: err}
+		}
+		fields.set(genMergedFlatBookField_Id)
+	}
+	if tmp, ok := base["title"]; ok {
+		obj.Title = tmp
+		fields.set(genMergedFlatBookField_Title)
+	}
+	if tmp, ok := base["author.id"]; ok {
+		if obj.Author.Id, err = m2s.ToInt64E(tmp); err != nil {
+			return fields, unknown, &m2s.KeyError{Key: "author.id", Err: err}
+		}
+		fields.set(genMergedFlatBookField_Author_Id)
+	}
+	if tmp, ok := base["author.name"]; ok {
+		obj.Author.Name = tmp
+		fields.set(genMergedFlatBookField_Author_Name)
+	}
+	if tmp, ok := base["publisher_name"]; ok {
+		if obj.Publisher == nil {
+			obj.Publisher = &model.Publisher{}
+			obj.Publisher.Default()
+		}
+		obj.Publisher.Name = tmp
+		fields.set(genMergedFlatBookField_Publisher_Name)
+	}
+	if tmp, ok := base["publisher_city"]; ok {
+		if obj.Publisher == nil {
+			obj.Publisher = &model.Publisher{}
+			obj.Publisher.Default()
+		}
+		obj.Publisher.City = tmp
+		fields.set(genMergedFlatBookField_Publisher_City)
+	}
+
+	// 带下标的 key 组成的切片，比如: items.0.title
+	var indexLen [2]int
+	for key := range base {
+		if idx, ok := m2s.KeyIndex(key, "chapters.", ".", genApplyMergedFlatBookFromBaseChaptersElemKey); ok && idx >= 1000 {
+			return fields, unknown, &m2s.KeyError{Key: key, Err: &m2s.ElemError{Index: idx, Err: m2s.ErrIndexRange}}
+		} else if ok && idx >= indexLen[0] {
+			indexLen[0] = idx + 1
+		}
+		if idx, ok := m2s.KeyIndex(key, "tags.", ".", nil); ok && idx >= 1000 {
+			return fields, unknown, &m2s.KeyError{Key: key, Err: &m2s.ElemError{Index: idx, Err: m2s.ErrIndexRange}}
+		} else if ok && idx >= indexLen[1] {
+			indexLen[1] = idx + 1
+		}
+	}
+	if n := indexLen[0]; n > 0 {
+		// 保留已有的元素，只在下标超出时追加
+		if n > len(obj.Chapters) {
+			obj.Chapters = append(obj.Chapters, make([]*model.Chapter, n-len(obj.Chapters))...)
+		}
+		for i := 0; i < n; i++ {
+			if obj.Chapters[i] == nil {
+				obj.Chapters[i] = &model.Chapter{}
+			}
+			if err = genApplyMergedFlatBookFromBaseChaptersElem(base, obj.Chapters[i], "chapters."+strconv.Itoa(i)+"."); err != nil {
+				return fields, unknown, err
+			}
+		}
+		fields.set(genMergedFlatBookField_Chapters)
+	}
+	if n := indexLen[1]; n > 0 {
+		// 保留已有的元素，只在下标超出时追加
+		if n > len(obj.Tags) {
+			obj.Tags = append(obj.Tags, make([]string, n-len(obj.Tags))...)
+		}
+		for i := 0; i < n; i++ {
+			key := "tags." + strconv.Itoa(i)
+			if tmp, ok := base[key]; ok {
+				obj.Tags[i] = tmp
+			}
+		}
+		fields.set(genMergedFlatBookField_Tags)
+	}
+
+	// 值为 JSON 字符串的字段
+	if tmp, ok := base["labels"]; ok {
+		if err = m2s.UnmarshalJSON(tmp, &obj.Labels); err != nil {
+			return fields, unknown, &m2s.KeyError{Key: "labels", Err: err}
+		}
+		fields.set(genMergedFlatBookField_Labels)
+	}
+	if tmp, ok := base["ext"]; ok {
+		if err = m2s.UnmarshalJSON(tmp, &obj.Ext); err != nil {
+			return fields, unknown, &m2s.KeyError{Key: "ext", Err: err}
+		}
+		fields.set(genMergedFlatBookField_Ext)
+	}
+	if tmp, ok := base["source"]; ok {
+		if err = m2s.UnmarshalJSON(tmp, &obj.Source); err != nil {
+			return fields, unknown, &m2s.KeyError{Key: "source", Err: err}
+		}
+		fields.set(genMergedFlatBookField_Source)
+	}
+
+	return fields, unknown, err
+}
+
+// genApplyMergedFlatBookFromBaseChaptersElem 只覆盖 base 中存在的字段
+func genApplyMergedFlatBookFromBaseChaptersElem(base map[string]string, obj *model.Chapter, prefix string) (err error) {
+	// 直接赋值的字段
+	if tmp, ok := base[prefix+"title"]; ok {
+		obj.Title = tmp
+	}
+	if tmp, ok := base[prefix+"pages"]; ok {
+		if obj.Pages, err = m2s.ToInt32E(tmp); err != nil {
+			return &m2s.KeyError{Key: prefix + "pages", Err: err}
+		}
+	}
+
+	// 需要手动处理的字段
+	// obj.Editor = ?
+
+	return err
+}
+
+// genApplyMergedFlatBookFromBaseChaptersElemKey 去掉元素前缀后的 key 是否为 model.Chapter 的字段
+func genApplyMergedFlatBookFromBaseChaptersElemKey(key string) bool {
+	switch key {
+	case "title", "pages", "editor":
+		return true
+	}
+	return false
+}
+
+// genApplyMergedFlatBookFromOverride 只覆盖 override 中存在的字段
+func genApplyMergedFlatBookFromOverride(override map[string]string, obj *model.FlatBook) (fields genMergedFlatBookFields, unknown []string, err error) {
+	// 检查未知的 key
+	for key := range override {
+		switch key {
+		case "id", "title", "author.id", "author.name", "publisher_name", "publisher_city", "labels", "ext", "source":
+		default:
+			if _, ok := m2s.KeyIndex(key, "chapters.", ".", genApplyMergedFlatBookFromOverrideChaptersElemKey); ok {
+				continue
+			}
+			if _, ok := m2s.KeyIndex(key, "tags.", ".", nil); ok {
+				continue
+			}
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+
+	// 直接赋值的字段
+	if tmp, ok := override["id"]; ok {
+		if obj.Id, err = m2s.ToInt64E(tmp); err != nil {
+			return fields, unknown, &m2s.KeyError{Key: "id", Err: err}
+		}
+		fields.set(genMergedFlatBookField_Id)
+	}
+	if tmp, ok := override["title"]; ok {
+		obj.Title = tmp
+		fields.set(genMergedFlatBookField_Title)
+	}
+	if tmp, ok := override["author.id"]; ok {
+		if obj.Author.Id, err = m2s.ToInt64E(tmp); err != nil {
+			return fields, unknown, &m2s.KeyError{Key: "author.id", Err: err}
+		}
+		fields.set(genMergedFlatBookField_Author_Id)
+	}
+	if tmp, ok := override["author.name"]; ok {
+		obj.Author.Name = tmp
+		fields.set(genMergedFlatBookField_Author_Name)
+	}
+	if tmp, ok := override["publisher_name"]; ok {
+		if obj.Publisher == nil {
+			obj.Publisher = &model.Publisher{}
+			obj.Publisher.Default()
+		}
+		obj.Publisher.Name = tmp
+		fields.set(genMergedFlatBookField_Publisher_Name)
+	}
+	if tmp, ok := override["publisher_city"]; ok {
+		if obj.Publisher == nil {
+			obj.Publisher = &model.Publisher{}
+			obj.Publisher.Default()
+		}
+		obj.Publisher.City = tmp
+		fields.set(genMergedFlatBookField_Publisher_City)
+	}
+
+	// 带下标的 key 组成的切片，比如: items.0.title
+	var indexLen [2]int
+	for key := range override {
+		if idx, ok := m2s.KeyIndex(key, "chapters.", ".", genApplyMergedFlatBookFromOverrideChaptersElemKey); ok && idx >= 1000 {
+			return fields, unknown, &m2s.KeyError{Key: key, Err: &m2s.ElemError{Index: idx, Err: m2s.ErrIndexRange}}
+		} else if ok && idx >= indexLen[0] {
+			indexLen[0] = idx + 1
+		}
+		if idx, ok := m2s.KeyIndex(key, "tags.", ".", nil); ok && idx >= 1000 {
+			return fields, unknown, &m2s.KeyError{Key: key, Err: &m2s.ElemError{Index: idx, Err: m2s.ErrIndexRange}}
+		} else if ok && idx >= indexLen[1] {
+			indexLen[1] = idx + 1
+		}
+	}
+	if n := indexLen[0]; n > 0 {
+		// 保留已有的元素，只在下标超出时追加
+		if n > len(obj.Chapters) {
+			obj.Chapters = append(obj.Chapters, make([]*model.Chapter, n-len(obj.Chapters))...)
+		}
+		for i := 0; i < n; i++ {
+			if obj.Chapters[i] == nil {
+				obj.Chapters[i] = &model.Chapter{}
+			}
+			if err = genApplyMergedFlatBookFromOverrideChaptersElem(override, obj.Chapters[i], "chapters."+strconv.Itoa(i)+"."); err != nil {
+				return fields, unknown, err
+			}
+		}
+		fields.set(genMergedFlatBookField_Chapters)
+	}
+	if n := indexLen[1]; n > 0 {
+		// 保留已有的元素，只在下标超出时追加
+		if n > len(obj.Tags) {
+			obj.Tags = append(obj.Tags, make([]string, n-len(obj.Tags))...)
+		}
+		for i := 0; i < n; i++ {
+			key := "tags." + strconv.Itoa(i)
+			if tmp, ok := override[key]; ok {
+				obj.Tags[i] = tmp
+			}
+		}
+		fields.set(genMergedFlatBookField_Tags)
+	}
+
+	// 值为 JSON 字符串的字段
+	if tmp, ok := override["labels"]; ok {
+		if err = m2s.UnmarshalJSON(tmp, &obj.Labels); err != nil {
+			return fields, unknown, &m2s.KeyError{Key: "labels", Err: err}
+		}
+		fields.set(genMergedFlatBookField_Labels)
+	}
+	if tmp, ok := override["ext"]; ok {
+		if err = m2s.UnmarshalJSON(tmp, &obj.Ext); err != nil {
+			return fields, unknown, &m2s.KeyError{Key: "ext", Err: err}
+		}
+		fields.set(genMergedFlatBookField_Ext)
+	}
+	if tmp, ok := override["source"]; ok {
+		if err = m2s.UnmarshalJSON(tmp, &obj.Source); err != nil {
+			return fields, unknown, &m2s.KeyError{Key: "source", Err: err}
+		}
+		fields.set(genMergedFlatBookField_Source)
+	}
+
+	return fields, unknown, err
+}
+
+// genApplyMergedFlatBookFromOverrideChaptersElem 只覆盖 override 中存在的字段
+func genApplyMergedFlatBookFromOverrideChaptersElem(override map[string]string, obj *model.Chapter, prefix string) (err error) {
+	// 直接赋值的字段
+	if tmp, ok := override[prefix+"title"]; ok {
+		obj.Title = tmp
+	}
+	if tmp, ok := override[prefix+"pages"]; ok {
+		if obj.Pages, err = m2s.ToInt32E(tmp); err != nil {
+			return &m2s.KeyError{Key: prefix + "pages", Err: err}
+		}
+	}
+
+	// 需要手动处理的字段
+	// obj.Editor = ?
+
+	return err
+}
+
+// genApplyMergedFlatBookFromOverrideChaptersElemKey 去掉元素前缀后的 key 是否为 model.Chapter 的字段
+func genApplyMergedFlatBookFromOverrideChaptersElemKey(key string) bool {
+	switch key {
+	case "title", "pages", "editor":
+		return true
+	}
+	return false
+}
+
+// genMergedFlatBookSources 记录每个字段的值来自第几个参数（从 0 开始），-1 表示都不存在
+type genMergedFlatBookSources [11]int8
+
+// From 字段的值来自第几个参数，-1 表示都不存在
+func (s genMergedFlatBookSources) From(f genMergedFlatBookField) int {
+	return int(s[f])
+}
+
+func (s *genMergedFlatBookSources) merge(fields *genMergedFlatBookFields, from genMergedFlatBookFields, idx int8) {
+	for f := range s {
+		if from.Has(genMergedFlatBookField(f)) {
+			s[f] = idx
+			fields.set(genMergedFlatBookField(f))
+		}
+	}
+}
+
+// genMapToMergedFlatBook 每个 key 只从存在该 key 的优先级最高（最后）的参数转换
+func genMapToMergedFlatBook(base map[string]string, override map[string]string) (obj *model.FlatBook, fields genMergedFlatBookFields, sources genMergedFlatBookSources, unknown []string, err error) {
+	obj = &model.FlatBook{}
+	for idx := range sources {
+		sources[idx] = -1
+	}
+	baseOwn := make(map[string]string, len(base))
+	for key, val := range base {
+		if _, ok := override[key]; ok {
+			continue
+		}
+		baseOwn[key] = val
+	}
+	fields0, unknown0, err := genApplyMergedFlatBookFromBase(baseOwn, obj)
+	if err != nil {
+		return nil, fields, sources, unknown, err
+	}
+	sources.merge(&fields, fields0, 0)
+	unknown = append(unknown, unknown0...)
+	fields1, unknown1, err := genApplyMergedFlatBookFromOverride(override, obj)
+	if err != nil {
+		return nil, fields, sources, unknown, err
+	}
+	sources.merge(&fields, fields1, 1)
+	unknown = append(unknown, unknown1...)
+	unknown = m2s.SortKeys(unknown)
+	return obj, fields, sources, unknown, nil
+}
@@ -0,0 +1,165 @@
+package flat
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+)
+
+func TestFlatBook(t *testing.T) {
+	src := map[string]string{
+		"id":               "1",
+		"title":            "go",
+		"author.name":      "rob",
+		"publisher_name":   "pub",
+		"chapters.0.title": "intro",
+		"chapters.1.pages": "12",
+		"tags.1":           "lang",
+		"tags.x":           "unknown",
+	}
+	obj, fields, unknown, err := genMapToFlatBook(src)
+	if err != nil {
+		t.Fatalf("convert failed. err=%v", err)
+	}
+	want := &model.FlatBook{
+		Id:        1,
+		Title:     "go",
+		Author:    model.Author{Name: "rob"},
+		Publisher: &model.Publisher{Name: "pub", City: "unknown"},
+		Chapters:  []*model.Chapter{{Title: "intro"}, {Pages: 12}},
+		Tags:      []string{"", "lang"},
+	}
+	if !reflect.DeepEqual(obj, want) {
+		t.Errorf("obj = %+v, want %+v", obj, want)
+	}
+	if !fields.Has(genFlatBookField_Author_Name) || fields.Has(genFlatBookField_Author_Id) || !fields.Has(genFlatBookField_Chapters) {
+		t.Errorf("unexpected fields. fields=%v", fields.Names())
+	}
+	if !reflect.DeepEqual(unknown, []string{"tags.x"}) {
+		t.Errorf("unknown = %v", unknown)
+	}
+
+	var ke *m2s.KeyError
+	_, _, _, err = genMapToFlatBook(map[string]string{"chapters.0.pages": "abc"})
+	if !errors.As(err, &ke) || ke.Key != "chapters.0.pages" {
+		t.Errorf("wrong type in chapter should fail. err=%v", err)
+	}
+
+	// 下标可以大于 key 的数量
+	obj, _, _, err = genMapToFlatBook(map[string]string{"id": "1", "chapters.2.title": "x"})
+	if err != nil || len(obj.Chapters) != 3 || obj.Chapters[2].Title != "x" {
+		t.Errorf("index beyond key count should be kept. obj=%+v err=%v", obj, err)
+	}
+
+	// 下标不能超过 -maxindex，严格模式下返回错误
+	var elemErr *m2s.ElemError
+	_, _, _, err = genMapToFlatBook(map[string]string{"tags.1000": "x"})
+	if !errors.As(err, &ke) || ke.Key != "tags.1000" || !errors.As(err, &elemErr) || elemErr.Index != 1000 || !errors.Is(err, m2s.ErrIndexRange) {
+		t.Errorf("index out of range should fail. err=%v", err)
+	}
+
+	// 下标之后必须是元素的字段
+	obj, _, unknown, _ = genMapToFlatBook(map[string]string{
+		"chapters.0.title":   "intro",
+		"chapters.3.garbage": "x",
+		"chapters.1title":    "x",
+		"tags.0":             "go",
+		"tags.2.name":        "x",
+	})
+	if len(obj.Chapters) != 1 || !reflect.DeepEqual(obj.Tags, []string{"go"}) {
+		t.Errorf("invalid sub key should not extend slices. chapters=%d tags=%v", len(obj.Chapters), obj.Tags)
+	}
+	if want := []string{"chapters.1title", "chapters.3.garbage", "tags.2.name"}; !reflect.DeepEqual(unknown, want) {
+		t.Errorf("unknown = %v, want %v", unknown, want)
+	}
+}
+
+func TestApplyFlatBookKeepsElements(t *testing.T) {
+	obj := &model.FlatBook{
+		Chapters: []*model.Chapter{{Title: "intro"}, {Title: "basics", Pages: 3}, nil},
+		Tags:     []string{"go", "lang"},
+	}
+	_, _, err := genApplyFlatBook(map[string]string{"chapters.0.pages": "12", "chapters.2.title": "end", "chapters.4.title": "more", "tags.0": "golang"}, obj)
+	if err != nil {
+		t.Fatalf("apply failed. err=%v", err)
+	}
+	// 已有的元素只覆盖存在的 key，nil 元素和下标超出的部分追加新元素
+	want := []*model.Chapter{{Title: "intro", Pages: 12}, {Title: "basics", Pages: 3}, {Title: "end"}, {}, {Title: "more"}}
+	if !reflect.DeepEqual(obj.Chapters, want) || !reflect.DeepEqual(obj.Tags, []string{"golang", "lang"}) {
+		t.Errorf("chapters=%+v tags=%v", obj.Chapters, obj.Tags)
+	}
+}
+
+func TestFlatBookJSON(t *testing.T) {
+	src := map[string]string{
+		"labels": `["a","b"]`,
+		"ext":    `{"k":"v"}`,
+		"source": `{"id":3,"name":"wiki"}`,
+	}
+	obj, fields, _, err := genMapToFlatBook(src)
+	if err != nil {
+		t.Fatalf("convert failed. err=%v", err)
+	}
+	if !reflect.DeepEqual(obj.Labels, []string{"a", "b"}) || obj.Ext["k"] != "v" || obj.Source == nil || obj.Source.Id != 3 {
+		t.Errorf("unexpected json fields. obj=%+v", obj)
+	}
+	if !fields.Has(genFlatBookField_Labels) || !fields.Has(genFlatBookField_Source) {
+		t.Errorf("unexpected fields. fields=%v", fields.Names())
+	}
+
+	var ke *m2s.KeyError
+	_, _, _, err = genMapToFlatBook(map[string]string{"labels": `{"a":1}`})
+	if !errors.As(err, &ke) || ke.Key != "labels" {
+		t.Errorf("invalid json should fail. err=%v", err)
+	}
+}
+
+func TestFlatChapter(t *testing.T) {
+	obj, _, _, err := genMapToFlatChapter(map[string]interface{}{"title": "intro", "editor.id": float64(7)})
+	if err != nil {
+		t.Fatalf("convert failed. err=%v", err)
+	}
+	if obj.Title != "intro" || obj.Editor == nil || obj.Editor.Id != 7 {
+		t.Errorf("unexpected chapter. obj=%+v", obj)
+	}
+}
+
+func TestMergedFlatBookUnknown(t *testing.T) {
+	base := map[string]string{"id": "1", "title": "go", "extra": "a", "tags.x": "b"}
+	override := map[string]string{"title": "go2", "extra": "c", "author.age": "d"}
+	obj, _, sources, unknown, err := genMapToMergedFlatBook(base, override)
+	if err != nil {
+		t.Fatalf("merge failed. err=%v", err)
+	}
+	if obj.Id != 1 || obj.Title != "go2" || sources.From(genMergedFlatBookField_Title) != 1 {
+		t.Errorf("obj = %+v", obj)
+	}
+	// 两个参数中都有的未知 key 只返回一次
+	if want := []string{"author.age", "extra", "tags.x"}; !reflect.DeepEqual(unknown, want) {
+		t.Errorf("unknown = %v, want %v", unknown, want)
+	}
+}
+
+func TestMergedFlatBookPriority(t *testing.T) {
+	// 同一个元素的字段来自不同的参数；base 中错误的 id 被 override 覆盖，不会转换
+	base := map[string]string{"id": "abc", "chapters.0.title": "intro"}
+	override := map[string]string{"id": "2", "chapters.0.pages": "12", "chapters.1.title": "more"}
+	obj, _, sources, _, err := genMapToMergedFlatBook(base, override)
+	if err != nil {
+		t.Fatalf("merge failed. err=%v", err)
+	}
+	want := []*model.Chapter{{Title: "intro", Pages: 12}, {Title: "more"}}
+	if obj.Id != 2 || !reflect.DeepEqual(obj.Chapters, want) || sources.From(genMergedFlatBookField_Id) != 1 {
+		t.Errorf("obj = %+v chapters = %+v", obj, obj.Chapters)
+	}
+
+	// 没有被覆盖的错误仍然返回
+	var ke *m2s.KeyError
+	_, _, _, _, err = genMapToMergedFlatBook(base, map[string]string{})
+	if !errors.As(err, &ke) || ke.Key != "id" {
+		t.Errorf("want key error for id. err=%v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package httpbind
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+)
+
+// TestDecodeDiff 多值 map 取第一个值、切片取所有的值，http.Header 的 key 规范化
+func TestDecodeDiff(t *testing.T) {
+	m2s.RegisterEnum(model.BookStatus(0), model.BookStatus_value)
+
+	cases := []url.Values{
+		{},
+		{"q": {"go", "rust"}, "page": {"2"}, "page_size": {"20"}, "tag": {"a", "b"}},
+		{"author_id": {"1", "2"}, "status": {"BOOK_STATUS_ONLINE"}, "statuses": {"1", "BOOK_STATUS_OFFLINE"}, "level": {"3"}},
+		{"q": {}, "tag": {}},
+		{"page": {"x"}},
+		{"author_id": {"1", "x"}},
+	}
+	for _, src := range cases {
+		want, wantErr := genMapToSearchRequest(src)
+
+		got := &model.SearchRequest{}
+		gotErr := m2s.Decode(src, got, m2s.Strict())
+		if (wantErr != nil) != (gotErr != nil) {
+			t.Errorf("src=%v gen err=%v, decode err=%v", src, wantErr, gotErr)
+			continue
+		}
+		if wantErr == nil && !reflect.DeepEqual(want, got) {
+			t.Errorf("src=%v gen=%+v, decode=%+v", src, want, got)
+		}
+	}
+
+	header := http.Header{}
+	header.Set("x-trace-id", "abc")
+	header.Set("x-user-id", "7")
+	header.Set("x-debug", "true")
+	header.Add("accept", "text/html")
+	header.Add("accept", "application/json")
+	want, err := genMapToRequestMeta(header)
+	if err != nil {
+		t.Fatalf("convert failed. err=%v", err)
+	}
+	got := &model.RequestMeta{}
+	if err = m2s.Decode(header, got, m2s.Strict()); err != nil {
+		t.Fatalf("decode failed. err=%v", err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("gen=%+v, decode=%+v", want, got)
+	}
+}
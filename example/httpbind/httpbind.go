@@ -0,0 +1,20 @@
+package httpbind
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/adyzng/gotool/example/model"
+)
+
+//go:generate map2struct -strict
+
+// MapToSearchRequest 绑定 url query 或 form
+func MapToSearchRequest(src url.Values) (*model.SearchRequest, error) {
+	return nil, nil
+}
+
+// MapToRequestMeta 绑定请求头
+func MapToRequestMeta(src http.Header) (*model.RequestMeta, error) {
+	return nil, nil
+}
@@ -0,0 +1,143 @@
+// Auto generated code, DO NOT EDIT.
+
+package httpbind
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+)
+
+func genMapToRequestMeta(src http.Header) (obj *model.RequestMeta, err error) {
+	obj = &model.RequestMeta{}
+	if err = genApplyRequestMeta(src, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// genApplyRequestMeta 只覆盖 src 中存在的字段
+func genApplyRequestMeta(src http.Header, obj *model.RequestMeta) (err error) {
+	// 直接赋值的字段
+	if vals := src["X-Trace-Id"]; len(vals) > 0 {
+		tmp := vals[0]
+		obj.TraceId = tmp
+	}
+	if vals := src["X-User-Id"]; len(vals) > 0 {
+		tmp := vals[0]
+		if obj.UserId, err = m2s.ToInt64E(tmp); err != nil {
+			return &m2s.KeyError{Key: "X-User-Id", Err: err}
+		}
+	}
+
+	// 带赋值表达式的（指针类型）
+	if vals := src["X-Debug"]; len(vals) > 0 {
+		tmp := vals[0]
+		val, err := m2s.ToBoolE(tmp)
+		if err != nil {
+			return &m2s.KeyError{Key: "X-Debug", Err: err}
+		}
+		obj.Debug = &val
+	}
+
+	// 切片字段，取所有的值或者按分隔符拆分
+	if tmp, ok := src["Accept"]; ok {
+		val := make([]string, 0, len(tmp))
+		for _, item := range tmp {
+			val = append(val, item)
+		}
+		obj.Accept = val
+	}
+
+	return err
+}
+
+func genMapToSearchRequest(src url.Values) (obj *model.SearchRequest, err error) {
+	obj = &model.SearchRequest{}
+	if err = genApplySearchRequest(src, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// genApplySearchRequest 只覆盖 src 中存在的字段
+func genApplySearchRequest(src url.Values, obj *model.SearchRequest) (err error) {
+	// 直接赋值的字段
+	if vals := src["q"]; len(vals) > 0 {
+		tmp := vals[0]
+		obj.Keyword = tmp
+	}
+	if vals := src["page"]; len(vals) > 0 {
+		tmp := vals[0]
+		if obj.Page, err = m2s.ToInt32E(tmp); err != nil {
+			return &m2s.KeyError{Key: "page", Err: err}
+		}
+	}
+
+	// 枚举类型
+	if vals := src["status"]; len(vals) > 0 {
+		tmp := vals[0]
+		num, err := m2s.ToEnumE(tmp, model.BookStatus_value)
+		if err != nil {
+			return &m2s.KeyError{Key: "status", Err: err}
+		}
+		val := (model.BookStatus)(num)
+		obj.Status = val
+	}
+
+	// 带赋值表达式的（指针类型）
+	if vals := src["page_size"]; len(vals) > 0 {
+		tmp := vals[0]
+		val, err := m2s.ToInt32E(tmp)
+		if err != nil {
+			return &m2s.KeyError{Key: "page_size", Err: err}
+		}
+		obj.PageSize = &val
+	}
+
+	// 切片字段，取所有的值或者按分隔符拆分
+	if tmp, ok := src["tag"]; ok {
+		val := make([]string, 0, len(tmp))
+		for _, item := range tmp {
+			val = append(val, item)
+		}
+		obj.Tags = val
+	}
+	if tmp, ok := src["author_id"]; ok {
+		val := make([]int64, 0, len(tmp))
+		for idx, item := range tmp {
+			v, err := m2s.ToInt64E(item)
+			if err != nil {
+				return &m2s.KeyError{Key: "author_id", Err: &m2s.ElemError{Index: idx, Err: err}}
+			}
+			val = append(val, v)
+		}
+		obj.AuthorId = val
+	}
+	if tmp, ok := src["statuses"]; ok {
+		val := make([]model.BookStatus, 0, len(tmp))
+		for idx, item := range tmp {
+			v, err := m2s.ToEnumE(item, model.BookStatus_value)
+			if err != nil {
+				return &m2s.KeyError{Key: "statuses", Err: &m2s.ElemError{Index: idx, Err: err}}
+			}
+			val = append(val, model.BookStatus(v))
+		}
+		obj.Statuses = val
+	}
+	if tmp, ok := src["level"]; ok {
+		val := make([]model.ItemStatus, 0, len(tmp))
+		for idx, item := range tmp {
+			v, err := m2s.ToInt64E(item)
+			if err != nil {
+				return &m2s.KeyError{Key: "level", Err: &m2s.ElemError{Index: idx, Err: err}}
+			}
+			val = append(val, model.ItemStatus(v))
+		}
+		obj.Levels = val
+	}
+
+	return err
+}
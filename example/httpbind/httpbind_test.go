@@ -0,0 +1,90 @@
+package httpbind
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+)
+
+func TestBindRequest(t *testing.T) {
+	r := httptest.NewRequest("GET", "/search?q=go&tag=a&tag=b&author_id=1&author_id=2&page_size=20", nil)
+	r.Header.Set("x-user-id", "7")
+	r.Header.Add("accept", "text/html")
+	r.Header.Add("accept", "application/json")
+
+	query, err := genMapToSearchRequest(r.URL.Query())
+	if err != nil {
+		t.Fatalf("bind query failed. err=%v", err)
+	}
+	if query.Keyword != "go" || !reflect.DeepEqual(query.Tags, []string{"a", "b"}) ||
+		!reflect.DeepEqual(query.AuthorId, []int64{1, 2}) || query.PageSize == nil || *query.PageSize != 20 {
+		t.Errorf("query = %+v", query)
+	}
+
+	meta, err := genMapToRequestMeta(r.Header)
+	if err != nil {
+		t.Fatalf("bind header failed. err=%v", err)
+	}
+	if meta.UserId != 7 || !reflect.DeepEqual(meta.Accept, []string{"text/html", "application/json"}) {
+		t.Errorf("meta = %+v", meta)
+	}
+
+	if _, err = genMapToSearchRequest(map[string][]string{"author_id": {"1", "x"}}); err == nil {
+		t.Errorf("invalid slice item should fail")
+	}
+}
+
+func TestBindEnumSlice(t *testing.T) {
+	src := url.Values{
+		"status":   {"BOOK_STATUS_OFFLINE"},
+		"statuses": {"BOOK_STATUS_ONLINE", "2"},
+		"level":    {"1", "0"},
+	}
+	query, err := genMapToSearchRequest(src)
+	if err != nil {
+		t.Fatalf("bind query failed. err=%v", err)
+	}
+	if query.Status != model.BookStatus_BOOK_STATUS_OFFLINE {
+		t.Errorf("status = %v", query.Status)
+	}
+	if want := []model.BookStatus{model.BookStatus_BOOK_STATUS_ONLINE, model.BookStatus_BOOK_STATUS_OFFLINE}; !reflect.DeepEqual(query.Statuses, want) {
+		t.Errorf("statuses = %v, want %v", query.Statuses, want)
+	}
+	if want := []model.ItemStatus{model.ItemStatus_ONLINE, model.ItemStatus_OFFLINE}; !reflect.DeepEqual(query.Levels, want) {
+		t.Errorf("levels = %v, want %v", query.Levels, want)
+	}
+
+	var keyErr *m2s.KeyError
+	var elemErr *m2s.ElemError
+	_, err = genMapToSearchRequest(url.Values{"statuses": {"BOOK_STATUS_ONLINE", "NOT_A_STATUS"}})
+	if !errors.As(err, &keyErr) || keyErr.Key != "statuses" || !errors.As(err, &elemErr) || elemErr.Index != 1 {
+		t.Errorf("want statuses[1] error. err=%v", err)
+	}
+}
+
+func TestBindHeaderCanonical(t *testing.T) {
+	h := http.Header{}
+	h.Set("x-debug", "true")
+	h.Set("x-trace-id", "abc")
+	meta, err := genMapToRequestMeta(h)
+	if err != nil {
+		t.Fatalf("bind header failed. err=%v", err)
+	}
+	if meta.TraceId != "abc" || meta.Debug == nil || !*meta.Debug || meta.Accept != nil {
+		t.Errorf("meta = %+v", meta)
+	}
+
+	// 非规范化的 key 不会被读取
+	if meta, err = genMapToRequestMeta(http.Header{"x-user-id": {"7"}}); err != nil || meta.UserId != 0 {
+		t.Errorf("meta = %+v err=%v", meta, err)
+	}
+	if _, err = genMapToRequestMeta(http.Header{"X-User-Id": {"x"}}); err == nil {
+		t.Errorf("invalid user id should fail")
+	}
+}
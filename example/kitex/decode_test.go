@@ -0,0 +1,41 @@
+package kitex
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+)
+
+// TestDecodeDiff thrift 结构体默认使用 thrift tag 的名字，required 字段不受 Strict 影响；
+// 反射找不到 NewApiItemInfo，赋值的结果通过 Apply 比较
+func TestDecodeDiff(t *testing.T) {
+	cases := []map[string]string{
+		{},
+		{"item_id": "7", "title": "go"},
+		{"item_id": "7", "title": "go", "status": "2", "score": "4.5", "lang": "en"},
+		{"item_id": "7", "name": "go"},
+		{"title": "go", "score": "x"},
+	}
+	for _, src := range cases {
+		want, wantErr := genMapToItemInfo(src)
+
+		gotErr := m2s.Decode(src, &model.ApiItemInfo{})
+		if (wantErr != nil) != (gotErr != nil) {
+			t.Errorf("src=%v gen err=%v, decode err=%v", src, wantErr, gotErr)
+			continue
+		}
+		if wantErr != nil {
+			continue
+		}
+		got := model.NewApiItemInfo()
+		if err := m2s.Apply(src, got); err != nil {
+			t.Errorf("src=%v apply err=%v", src, err)
+			continue
+		}
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("src=%v gen=%+v, decode=%+v", src, want, got)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package kitex
+
+import (
+	"github.com/adyzng/gotool/example/model"
+)
+
+//go:generate map2struct
+
+// MapToItemInfo kitex 生成的结构体：key 默认取 thrift tag，使用 NewApiItemInfo() 保留 IDL 默认值，检查 required 字段
+func MapToItemInfo(src map[string]string) (*model.ApiItemInfo, error) {
+	return nil, nil
+}
@@ -0,0 +1,50 @@
+// Auto generated code, DO NOT EDIT.
+
+package kitex
+
+import (
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+)
+
+func genMapToItemInfo(src map[string]string) (obj *model.ApiItemInfo, err error) {
+	if _, ok := src["item_id"]; !ok {
+		return nil, &m2s.KeyError{Key: "item_id", Err: m2s.ErrMissingKey}
+	}
+	if _, ok := src["title"]; !ok {
+		return nil, &m2s.KeyError{Key: "title", Err: m2s.ErrMissingKey}
+	}
+	obj = model.NewApiItemInfo()
+	if err = genApplyItemInfo(src, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// genApplyItemInfo 只覆盖 src 中存在的字段
+func genApplyItemInfo(src map[string]string, obj *model.ApiItemInfo) (err error) {
+	// 直接赋值的字段
+	if tmp, ok := src["item_id"]; ok {
+		obj.ItemId = m2s.ToInt64(tmp)
+	}
+	if tmp, ok := src["title"]; ok {
+		obj.Title = tmp
+	}
+	if tmp, ok := src["lang"]; ok {
+		obj.Lang = tmp
+	}
+
+	// 枚举类型
+	if tmp, ok := src["status"]; ok {
+		val := (model.ItemStatus)(m2s.ToInt64(tmp))
+		obj.Status = val
+	}
+
+	// 带赋值表达式的（指针类型）
+	if tmp, ok := src["score"]; ok {
+		val := m2s.ToFloat64(tmp)
+		obj.Score = &val
+	}
+
+	return err
+}
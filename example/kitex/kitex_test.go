@@ -0,0 +1,43 @@
+package kitex
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+)
+
+func TestMapToItemInfo(t *testing.T) {
+	obj, err := genMapToItemInfo(map[string]string{"item_id": "7", "title": "go", "score": "4.5"})
+	if err != nil {
+		t.Fatalf("convert failed. err=%v", err)
+	}
+	// key 取 thrift tag 中的 title，而不是 json tag 中的 name
+	if obj.ItemId != 7 || obj.Title != "go" || obj.Score == nil || *obj.Score != 4.5 {
+		t.Errorf("unexpected item. obj=%+v", obj)
+	}
+	// optional 字段缺失时保留 NewApiItemInfo 的默认值
+	if obj.Status != model.ItemStatus_ONLINE || obj.Lang != "zh" {
+		t.Errorf("defaults lost. obj=%+v", obj)
+	}
+
+	obj, err = genMapToItemInfo(map[string]string{"item_id": "7", "title": "go", "status": "0", "lang": "en"})
+	if err != nil || obj.Status != model.ItemStatus_OFFLINE || obj.Lang != "en" {
+		t.Errorf("optional fields not applied. obj=%+v err=%v", obj, err)
+	}
+}
+
+func TestMapToItemInfoRequired(t *testing.T) {
+	var keyErr *m2s.KeyError
+	_, err := genMapToItemInfo(map[string]string{"item_id": "7", "name": "go"})
+	if !errors.As(err, &keyErr) || keyErr.Key != "title" || !errors.Is(err, m2s.ErrMissingKey) {
+		t.Errorf("want missing title. err=%v", err)
+	}
+
+	// genApplyItemInfo 保持 PATCH 语义，不检查 required
+	obj := model.NewApiItemInfo()
+	if err = genApplyItemInfo(map[string]string{"lang": "en"}, obj); err != nil || obj.Lang != "en" {
+		t.Errorf("apply should not check required. obj=%+v err=%v", obj, err)
+	}
+}
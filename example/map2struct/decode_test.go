@@ -0,0 +1,33 @@
+package map2struct
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+)
+
+// TestDecodeDiff 生成代码与 m2s.Decode 的结果必须一致（非严格模式）
+func TestDecodeDiff(t *testing.T) {
+	cases := []map[string]interface{}{
+		{},
+		{"book_id": float64(42), "book_name": "go", "is_first_read": true},
+		{"book_id": json.Number("9007199254740993"), "serial_count": "12", "book_type": float64(2)},
+		{"latest_read_time": float64(1.5), "category": 1, "is_first_read": "maybe"},
+		{"serial_count": json.Number("2147483648"), "copyright_info": nil},
+	}
+	for _, src := range cases {
+		want, _ := genMapToBookInfo(src)
+
+		got := &model.ApiBookInfo{}
+		if err := m2s.Decode(src, got); err != nil {
+			t.Errorf("src=%v decode err=%v", src, err)
+			continue
+		}
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("src=%v gen=%+v, decode=%+v", src, want, got)
+		}
+	}
+}
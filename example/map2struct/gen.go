@@ -6,7 +6,7 @@ import (
 	"github.com/adyzng/gotool/example/model"
 )
 
-//go:generate map2struct
+//go:generate map2struct -slice -test
 
 // MapToBookInfo ...
 func MapToBookInfo(ctx context.Context, src map[string]interface{}) (*model.ApiBookInfo, error) {
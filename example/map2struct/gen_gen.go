@@ -4,39 +4,77 @@ package map2struct
 
 import (
 	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
 	"github.com/spf13/cast"
 )
 
 func genMapToBookInfo(src map[string]interface{}) (obj *model.ApiBookInfo, err error) {
 	obj = &model.ApiBookInfo{}
+	if err = genApplyBookInfo(src, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
 
+// genApplyBookInfo 只覆盖 src 中存在的字段
+func genApplyBookInfo(src map[string]interface{}, obj *model.ApiBookInfo) (err error) {
 	// 直接赋值的字段
-	obj.Id = cast.ToInt64(src[""])
-	obj.Name = cast.ToString(src[""])
-	obj.CopyrightInfo = cast.ToString(src[""])
-	obj.CreateTime = cast.ToString(src[""])
-	obj.ThumbUrl = cast.ToString(src[""])
-	obj.IsFirstRead = cast.ToBool(src[""])
+	if tmp, ok := src["book_id"]; ok {
+		obj.Id = m2s.ToInt64(tmp)
+	}
+	if tmp, ok := src["book_name"]; ok {
+		obj.Name = cast.ToString(tmp)
+	}
+	if tmp, ok := src["copyright_info"]; ok {
+		obj.CopyrightInfo = cast.ToString(tmp)
+	}
+	if tmp, ok := src["create_time"]; ok {
+		obj.CreateTime = cast.ToString(tmp)
+	}
+	if tmp, ok := src["thumb_url"]; ok {
+		obj.ThumbUrl = cast.ToString(tmp)
+	}
+	if tmp, ok := src["is_first_read"]; ok {
+		obj.IsFirstRead = m2s.ToBool(tmp)
+	}
 
 	// 枚举类型
-	if tmp, ok := src[""]; ok {
-		val := (model.BookType)(cast.ToInt64(tmp))
+	if tmp, ok := src["book_type"]; ok {
+		val := (model.BookType)(m2s.ToInt64(tmp))
 		obj.BookType = &val
 	}
 
 	// 带赋值表达式的（指针类型）
-	if tmp, ok := src[""]; ok {
-		val := cast.ToInt32(tmp)
+	if tmp, ok := src["serial_count"]; ok {
+		val := m2s.ToInt32(tmp)
 		obj.SerialCount = &val
 	}
-	if tmp, ok := src[""]; ok {
-		val := cast.ToInt64(tmp)
+	if tmp, ok := src["latest_read_time"]; ok {
+		val := m2s.ToInt64(tmp)
 		obj.LatestReadTime = &val
 	}
-	if tmp, ok := src[""]; ok {
+	if tmp, ok := src["category"]; ok {
 		val := cast.ToString(tmp)
 		obj.Category = &val
 	}
 
-	return obj, err
+	return err
+}
+
+// genMapToBookInfoSlice 按顺序批量转换，失败时返回 *m2s.RowError
+func genMapToBookInfoSlice(srcs []map[string]interface{}) ([]*model.ApiBookInfo, error) {
+	return genMapToBookInfoSliceParallel(srcs, 1)
+}
+
+// genMapToBookInfoSliceParallel 最多 workers 个 goroutine 并发转换，结果顺序与 srcs 一致；workers <= 1 时顺序转换
+func genMapToBookInfoSliceParallel(srcs []map[string]interface{}, workers int) ([]*model.ApiBookInfo, error) {
+	list := make([]*model.ApiBookInfo, len(srcs))
+	err := m2s.ForEach(len(srcs), workers, func(idx int) (err error) {
+		list[idx], err = genMapToBookInfo(srcs[idx])
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
 }
@@ -0,0 +1,274 @@
+// Auto generated code, DO NOT EDIT.
+
+//go:build go1.18
+// +build go1.18
+
+package map2struct
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/adyzng/gotool/example/model"
+)
+
+func testGenBookInfoSrc() map[string]interface{} {
+	return map[string]interface{}{
+		"book_id":          float64(42),
+		"book_name":        "abc",
+		"copyright_info":   "abc",
+		"create_time":      "abc",
+		"serial_count":     float64(42),
+		"thumb_url":        "abc",
+		"book_type":        float64(42),
+		"latest_read_time": float64(42),
+		"category":         "abc",
+		"is_first_read":    true,
+	}
+}
+
+func testGenBookInfoSrcWith(key string, val interface{}) map[string]interface{} {
+	src := testGenBookInfoSrc()
+	src[key] = val
+	return src
+}
+
+func TestGenMapToBookInfo(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     map[string]interface{}
+		wantErr bool
+		check   func(t *testing.T, obj *model.ApiBookInfo)
+	}{
+		{
+			name: "valid",
+			src:  testGenBookInfoSrc(),
+			check: func(t *testing.T, obj *model.ApiBookInfo) {
+				if obj.Id != int64(42) {
+					t.Errorf("Id = %v, want %v", obj.Id, int64(42))
+				}
+				if obj.Name != "abc" {
+					t.Errorf("Name = %v, want %v", obj.Name, "abc")
+				}
+				if obj.CopyrightInfo != "abc" {
+					t.Errorf("CopyrightInfo = %v, want %v", obj.CopyrightInfo, "abc")
+				}
+				if obj.CreateTime != "abc" {
+					t.Errorf("CreateTime = %v, want %v", obj.CreateTime, "abc")
+				}
+				if obj.SerialCount == nil || *obj.SerialCount != int32(42) {
+					t.Errorf("SerialCount = %v, want %v", obj.SerialCount, int32(42))
+				}
+				if obj.ThumbUrl != "abc" {
+					t.Errorf("ThumbUrl = %v, want %v", obj.ThumbUrl, "abc")
+				}
+				if obj.BookType == nil || *obj.BookType != model.BookType(42) {
+					t.Errorf("BookType = %v, want %v", obj.BookType, model.BookType(42))
+				}
+				if obj.LatestReadTime == nil || *obj.LatestReadTime != int64(42) {
+					t.Errorf("LatestReadTime = %v, want %v", obj.LatestReadTime, int64(42))
+				}
+				if obj.Category == nil || *obj.Category != "abc" {
+					t.Errorf("Category = %v, want %v", obj.Category, "abc")
+				}
+				if obj.IsFirstRead != true {
+					t.Errorf("IsFirstRead = %v, want %v", obj.IsFirstRead, true)
+				}
+			},
+		},
+		{
+			name: "missing",
+			src:  map[string]interface{}{},
+			check: func(t *testing.T, obj *model.ApiBookInfo) {
+				want := &model.ApiBookInfo{}
+				if !reflect.DeepEqual(obj, want) {
+					t.Errorf("obj = %+v, want %+v", obj, want)
+				}
+			},
+		},
+		{
+			name: "wrong_type/book_id",
+			src:  testGenBookInfoSrcWith("book_id", "not-a-number"),
+		},
+		{
+			name: "min/book_id",
+			src:  testGenBookInfoSrcWith("book_id", json.Number("-9223372036854775808")),
+			check: func(t *testing.T, obj *model.ApiBookInfo) {
+				if obj.Id != int64(-9223372036854775808) {
+					t.Errorf("Id = %v, want %v", obj.Id, int64(-9223372036854775808))
+				}
+			},
+		},
+		{
+			name: "max/book_id",
+			src:  testGenBookInfoSrcWith("book_id", json.Number("9223372036854775807")),
+			check: func(t *testing.T, obj *model.ApiBookInfo) {
+				if obj.Id != int64(9223372036854775807) {
+					t.Errorf("Id = %v, want %v", obj.Id, int64(9223372036854775807))
+				}
+			},
+		},
+		{
+			name: "overflow/book_id",
+			src:  testGenBookInfoSrcWith("book_id", json.Number("9223372036854775808")),
+		},
+		{
+			name: "fraction/book_id",
+			src:  testGenBookInfoSrcWith("book_id", float64(1.5)),
+		},
+		{
+			name: "lossy/book_id",
+			src:  testGenBookInfoSrcWith("book_id", float64(1<<60)),
+		},
+		{
+			name: "wrong_type/serial_count",
+			src:  testGenBookInfoSrcWith("serial_count", "not-a-number"),
+		},
+		{
+			name: "min/serial_count",
+			src:  testGenBookInfoSrcWith("serial_count", json.Number("-2147483648")),
+			check: func(t *testing.T, obj *model.ApiBookInfo) {
+				if obj.SerialCount == nil || *obj.SerialCount != int32(-2147483648) {
+					t.Errorf("SerialCount = %v, want %v", obj.SerialCount, int32(-2147483648))
+				}
+			},
+		},
+		{
+			name: "max/serial_count",
+			src:  testGenBookInfoSrcWith("serial_count", json.Number("2147483647")),
+			check: func(t *testing.T, obj *model.ApiBookInfo) {
+				if obj.SerialCount == nil || *obj.SerialCount != int32(2147483647) {
+					t.Errorf("SerialCount = %v, want %v", obj.SerialCount, int32(2147483647))
+				}
+			},
+		},
+		{
+			name: "overflow/serial_count",
+			src:  testGenBookInfoSrcWith("serial_count", json.Number("2147483648")),
+		},
+		{
+			name: "fraction/serial_count",
+			src:  testGenBookInfoSrcWith("serial_count", float64(1.5)),
+		},
+		{
+			name: "wrong_type/book_type",
+			src:  testGenBookInfoSrcWith("book_type", "not-a-number"),
+		},
+		{
+			name: "min/book_type",
+			src:  testGenBookInfoSrcWith("book_type", json.Number("-9223372036854775808")),
+			check: func(t *testing.T, obj *model.ApiBookInfo) {
+				if obj.BookType == nil || *obj.BookType != model.BookType(-9223372036854775808) {
+					t.Errorf("BookType = %v, want %v", obj.BookType, model.BookType(-9223372036854775808))
+				}
+			},
+		},
+		{
+			name: "max/book_type",
+			src:  testGenBookInfoSrcWith("book_type", json.Number("9223372036854775807")),
+			check: func(t *testing.T, obj *model.ApiBookInfo) {
+				if obj.BookType == nil || *obj.BookType != model.BookType(9223372036854775807) {
+					t.Errorf("BookType = %v, want %v", obj.BookType, model.BookType(9223372036854775807))
+				}
+			},
+		},
+		{
+			name: "overflow/book_type",
+			src:  testGenBookInfoSrcWith("book_type", json.Number("9223372036854775808")),
+		},
+		{
+			name: "fraction/book_type",
+			src:  testGenBookInfoSrcWith("book_type", float64(1.5)),
+		},
+		{
+			name: "lossy/book_type",
+			src:  testGenBookInfoSrcWith("book_type", float64(1<<60)),
+		},
+		{
+			name: "wrong_type/latest_read_time",
+			src:  testGenBookInfoSrcWith("latest_read_time", "not-a-number"),
+		},
+		{
+			name: "min/latest_read_time",
+			src:  testGenBookInfoSrcWith("latest_read_time", json.Number("-9223372036854775808")),
+			check: func(t *testing.T, obj *model.ApiBookInfo) {
+				if obj.LatestReadTime == nil || *obj.LatestReadTime != int64(-9223372036854775808) {
+					t.Errorf("LatestReadTime = %v, want %v", obj.LatestReadTime, int64(-9223372036854775808))
+				}
+			},
+		},
+		{
+			name: "max/latest_read_time",
+			src:  testGenBookInfoSrcWith("latest_read_time", json.Number("9223372036854775807")),
+			check: func(t *testing.T, obj *model.ApiBookInfo) {
+				if obj.LatestReadTime == nil || *obj.LatestReadTime != int64(9223372036854775807) {
+					t.Errorf("LatestReadTime = %v, want %v", obj.LatestReadTime, int64(9223372036854775807))
+				}
+			},
+		},
+		{
+			name: "overflow/latest_read_time",
+			src:  testGenBookInfoSrcWith("latest_read_time", json.Number("9223372036854775808")),
+		},
+		{
+			name: "fraction/latest_read_time",
+			src:  testGenBookInfoSrcWith("latest_read_time", float64(1.5)),
+		},
+		{
+			name: "lossy/latest_read_time",
+			src:  testGenBookInfoSrcWith("latest_read_time", float64(1<<60)),
+		},
+		{
+			name: "wrong_type/is_first_read",
+			src:  testGenBookInfoSrcWith("is_first_read", "maybe"),
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			obj, err := genMapToBookInfo(c.src)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, c.wantErr)
+			}
+			if err == nil && c.check != nil {
+				c.check(t, obj)
+			}
+		})
+	}
+}
+
+func BenchmarkGenMapToBookInfo(b *testing.B) {
+	src := testGenBookInfoSrc()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = genMapToBookInfo(src)
+	}
+}
+
+// FuzzGenMapToBookInfo 生成的代码对任意输入都不能 panic
+func FuzzGenMapToBookInfo(f *testing.F) {
+	keys := []string{
+		"book_id",
+		"book_name",
+		"copyright_info",
+		"create_time",
+		"serial_count",
+		"thumb_url",
+		"book_type",
+		"latest_read_time",
+		"category",
+		"is_first_read",
+	}
+	f.Add("42", int64(42), 1.5, true)
+	f.Add("", int64(-1), -0.5, false)
+	f.Fuzz(func(t *testing.T, str string, num int64, flt float64, flag bool) {
+		vals := []interface{}{str, num, flt, flag, json.Number(str), nil}
+		src := map[string]interface{}{str: vals[0]}
+		for idx, key := range keys {
+			src[key] = vals[(idx+int(uint8(num)))%len(vals)]
+		}
+		_, _ = genMapToBookInfo(src)
+	})
+}
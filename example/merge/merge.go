@@ -0,0 +1,17 @@
+package merge
+
+import (
+	"github.com/adyzng/gotool/example/model"
+)
+
+//go:generate map2struct -strict -presence
+
+// MapToLayeredConfig 配置文件中的值作为基础，命令行参数覆盖
+func MapToLayeredConfig(file map[string]string, flags map[string]interface{}) (*model.ServerConfig, error) {
+	return nil, nil
+}
+
+// MapToMergedItem 必须的字段只要在任一参数中存在即可
+func MapToMergedItem(base, override map[string]interface{}) (*model.ApiItemInfo, error) {
+	return nil, nil
+}
@@ -0,0 +1,347 @@
+// Auto generated code, DO NOT EDIT.
+
+package merge
+
+import (
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+	"github.com/spf13/cast"
+)
+
+// genLayeredConfigField 标识 model.ServerConfig 的字段
+type genLayeredConfigField uint
+
+const (
+	genLayeredConfigField_Host genLayeredConfigField = iota
+	genLayeredConfigField_Port
+	genLayeredConfigField_TimeoutMs
+	genLayeredConfigField_Debug
+)
+
+var genLayeredConfigFieldNames = [...]string{
+	"Host",
+	"Port",
+	"TimeoutMs",
+	"Debug",
+}
+
+func (f genLayeredConfigField) String() string {
+	return genLayeredConfigFieldNames[f]
+}
+
+// genLayeredConfigFields 记录 file 中存在的字段
+type genLayeredConfigFields [1]uint64
+
+func (fs *genLayeredConfigFields) set(f genLayeredConfigField) {
+	fs[f/64] |= 1 << (f % 64)
+}
+
+// Has 字段是否出现在 file 中
+func (fs genLayeredConfigFields) Has(f genLayeredConfigField) bool {
+	return fs[f/64]&(1<<(f%64)) != 0
+}
+
+// Names 出现在 file 中的字段名
+func (fs genLayeredConfigFields) Names() []string {
+	names := make([]string, 0, len(genLayeredConfigFieldNames))
+	for idx, name := range genLayeredConfigFieldNames {
+		if fs.Has(genLayeredConfigField(idx)) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// genApplyLayeredConfigFromFile 只覆盖 file 中存在的字段
+func genApplyLayeredConfigFromFile(file map[string]string, obj *model.ServerConfig) (fields genLayeredConfigFields, err error) {
+	// 直接赋值的字段
+	if tmp, ok := file["host"]; ok {
+		obj.Host = tmp
+		fields.set(genLayeredConfigField_Host)
+	}
+	if tmp, ok := file["port"]; ok {
+		if obj.Port, err = m2s.ToIntE(tmp); err != nil {
+			return fields, &m2s.KeyError{Key: "port", Err: err}
+		}
+		fields.set(genLayeredConfigField_Port)
+	}
+	if tmp, ok := file["timeout_ms"]; ok {
+		if obj.TimeoutMs, err = m2s.ToInt64E(tmp); err != nil {
+			return fields, &m2s.KeyError{Key: "timeout_ms", Err: err}
+		}
+		fields.set(genLayeredConfigField_TimeoutMs)
+	}
+	if tmp, ok := file["Debug"]; ok {
+		if obj.Debug, err = m2s.ToBoolE(tmp); err != nil {
+			return fields, &m2s.KeyError{Key: "Debug", Err: err}
+		}
+		fields.set(genLayeredConfigField_Debug)
+	}
+
+	return fields, err
+}
+
+// genApplyLayeredConfigFromFlags 只覆盖 flags 中存在的字段
+func genApplyLayeredConfigFromFlags(flags map[string]interface{}, obj *model.ServerConfig) (fields genLayeredConfigFields, err error) {
+	// 直接赋值的字段
+	if tmp, ok := flags["host"]; ok {
+		if obj.Host, err = cast.ToStringE(tmp); err != nil {
+			return fields, &m2s.KeyError{Key: "host", Err: err}
+		}
+		fields.set(genLayeredConfigField_Host)
+	}
+	if tmp, ok := flags["port"]; ok {
+		if obj.Port, err = m2s.ToIntE(tmp); err != nil {
+			return fields, &m2s.KeyError{Key: "port", Err: err}
+		}
+		fields.set(genLayeredConfigField_Port)
+	}
+	if tmp, ok := flags["timeout_ms"]; ok {
+		if obj.TimeoutMs, err = m2s.ToInt64E(tmp); err != nil {
+			return fields, &m2s.KeyError{Key: "timeout_ms", Err: err}
+		}
+		fields.set(genLayeredConfigField_TimeoutMs)
+	}
+	if tmp, ok := flags["Debug"]; ok {
+		if obj.Debug, err = m2s.ToBoolE(tmp); err != nil {
+			return fields, &m2s.KeyError{Key: "Debug", Err: err}
+		}
+		fields.set(genLayeredConfigField_Debug)
+	}
+
+	return fields, err
+}
+
+// genLayeredConfigSources 记录每个字段的值来自第几个参数（从 0 开始），-1 表示都不存在
+type genLayeredConfigSources [4]int8
+
+// From 字段的值来自第几个参数，-1 表示都不存在
+func (s genLayeredConfigSources) From(f genLayeredConfigField) int {
+	return int(s[f])
+}
+
+func (s *genLayeredConfigSources) merge(fields *genLayeredConfigFields, from genLayeredConfigFields, idx int8) {
+	for f := range s {
+		if from.Has(genLayeredConfigField(f)) {
+			s[f] = idx
+			fields.set(genLayeredConfigField(f))
+		}
+	}
+}
+
+// genMapToLayeredConfig 每个 key 只从存在该 key 的优先级最高（最后）的参数转换
+func genMapToLayeredConfig(file map[string]string, flags map[string]interface{}) (obj *model.ServerConfig, fields genLayeredConfigFields, sources genLayeredConfigSources, err error) {
+	obj = model.NewServerConfig()
+	for idx := range sources {
+		sources[idx] = -1
+	}
+	fileOwn := make(map[string]string, len(file))
+	for key, val := range file {
+		if _, ok := flags[key]; ok {
+			continue
+		}
+		fileOwn[key] = val
+	}
+	fields0, err := genApplyLayeredConfigFromFile(fileOwn, obj)
+	if err != nil {
+		return nil, fields, sources, err
+	}
+	sources.merge(&fields, fields0, 0)
+	fields1, err := genApplyLayeredConfigFromFlags(flags, obj)
+	if err != nil {
+		return nil, fields, sources, err
+	}
+	sources.merge(&fields, fields1, 1)
+	return obj, fields, sources, nil
+}
+
+// genMergedItemField 标识 model.ApiItemInfo 的字段
+type genMergedItemField uint
+
+const (
+	genMergedItemField_ItemId genMergedItemField = iota
+	genMergedItemField_Title
+	genMergedItemField_Status
+	genMergedItemField_Score
+	genMergedItemField_Lang
+)
+
+var genMergedItemFieldNames = [...]string{
+	"ItemId",
+	"Title",
+	"Status",
+	"Score",
+	"Lang",
+}
+
+func (f genMergedItemField) String() string {
+	return genMergedItemFieldNames[f]
+}
+
+// genMergedItemFields 记录 base 中存在的字段
+type genMergedItemFields [1]uint64
+
+func (fs *genMergedItemFields) set(f genMergedItemField) {
+	fs[f/64] |= 1 << (f % 64)
+}
+
+// Has 字段是否出现在 base 中
+func (fs genMergedItemFields) Has(f genMergedItemField) bool {
+	return fs[f/64]&(1<<(f%64)) != 0
+}
+
+// Names 出现在 base 中的字段名
+func (fs genMergedItemFields) Names() []string {
+	names := make([]string, 0, len(genMergedItemFieldNames))
+	for idx, name := range genMergedItemFieldNames {
+		if fs.Has(genMergedItemField(idx)) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// genApplyMergedItemFromBase 只覆盖 base 中存在的字段
+func genApplyMergedItemFromBase(base map[string]interface{}, obj *model.ApiItemInfo) (fields genMergedItemFields, err error) {
+	// 直接赋值的字段
+	if tmp, ok := base["item_id"]; ok {
+		if obj.ItemId, err = m2s.ToInt64E(tmp); err != nil {
+			return fields, &m2s.KeyError{Key: "item_id", Err: err}
+		}
+		fields.set(genMergedItemField_ItemId)
+	}
+	if tmp, ok := base["title"]; ok {
+		if obj.Title, err = cast.ToStringE(tmp); err != nil {
+			return fields, &m2s.KeyError{Key: "title", Err: err}
+		}
+		fields.set(genMergedItemField_Title)
+	}
+	if tmp, ok := base["lang"]; ok {
+		if obj.Lang, err = cast.ToStringE(tmp); err != nil {
+			return fields, &m2s.KeyError{Key: "lang", Err: err}
+		}
+		fields.set(genMergedItemField_Lang)
+	}
+
+	// 枚举类型
+	if tmp, ok := base["status"]; ok {
+		num, err := m2s.ToInt64E(tmp)
+		if err != nil {
+			return fields, &m2s.KeyError{Key: "status", Err: err}
+		}
+		val := (model.ItemStatus)(num)
+		obj.Status = val
+		fields.set(genMergedItemField_Status)
+	}
+
+	// 带赋值表达式的（指针类型）
+	if tmp, ok := base["score"]; ok {
+		val, err := m2s.ToFloat64E(tmp)
+		if err != nil {
+			return fields, &m2s.KeyError{Key: "score", Err: err}
+		}
+		obj.Score = &val
+		fields.set(genMergedItemField_Score)
+	}
+
+	return fields, err
+}
+
+// genApplyMergedItemFromOverride 只覆盖 override 中存在的字段
+func genApplyMergedItemFromOverride(override map[string]interface{}, obj *model.ApiItemInfo) (fields genMergedItemFields, err error) {
+	// 直接赋值的字段
+	if tmp, ok := override["item_id"]; ok {
+		if obj.ItemId, err = m2s.ToInt64E(tmp); err != nil {
+			return fields, &m2s.KeyError{Key: "item_id", Err: err}
+		}
+		fields.set(genMergedItemField_ItemId)
+	}
+	if tmp, ok := override["title"]; ok {
+		if obj.Title, err = cast.ToStringE(tmp); err != nil {
+			return fields, &m2s.KeyError{Key: "title", Err: err}
+		}
+		fields.set(genMergedItemField_Title)
+	}
+	if tmp, ok := override["lang"]; ok {
+		if obj.Lang, err = cast.ToStringE(tmp); err != nil {
+			return fields, &m2s.KeyError{Key: "lang", Err: err}
+		}
+		fields.set(genMergedItemField_Lang)
+	}
+
+	// 枚举类型
+	if tmp, ok := override["status"]; ok {
+		num, err := m2s.ToInt64E(tmp)
+		if err != nil {
+			return fields, &m2s.KeyError{Key: "status", Err: err}
+		}
+		val := (model.ItemStatus)(num)
+		obj.Status = val
+		fields.set(genMergedItemField_Status)
+	}
+
+	// 带赋值表达式的（指针类型）
+	if tmp, ok := override["score"]; ok {
+		val, err := m2s.ToFloat64E(tmp)
+		if err != nil {
+			return fields, &m2s.KeyError{Key: "score", Err: err}
+		}
+		obj.Score = &val
+		fields.set(genMergedItemField_Score)
+	}
+
+	return fields, err
+}
+
+// genMergedItemSources 记录每个字段的值来自第几个参数（从 0 开始），-1 表示都不存在
+type genMergedItemSources [5]int8
+
+// From 字段的值来自第几个参数，-1 表示都不存在
+func (s genMergedItemSources) From(f genMergedItemField) int {
+	return int(s[f])
+}
+
+func (s *genMergedItemSources) merge(fields *genMergedItemFields, from genMergedItemFields, idx int8) {
+	for f := range s {
+		if from.Has(genMergedItemField(f)) {
+			s[f] = idx
+			fields.set(genMergedItemField(f))
+		}
+	}
+}
+
+// genMapToMergedItem 每个 key 只从存在该 key 的优先级最高（最后）的参数转换
+func genMapToMergedItem(base map[string]interface{}, override map[string]interface{}) (obj *model.ApiItemInfo, fields genMergedItemFields, sources genMergedItemSources, err error) {
+	if _, ok := base["item_id"]; !ok {
+		if _, ok := override["item_id"]; !ok {
+			return nil, fields, sources, &m2s.KeyError{Key: "item_id", Err: m2s.ErrMissingKey}
+		}
+	}
+	if _, ok := base["title"]; !ok {
+		if _, ok := override["title"]; !ok {
+			return nil, fields, sources, &m2s.KeyError{Key: "title", Err: m2s.ErrMissingKey}
+		}
+	}
+	obj = model.NewApiItemInfo()
+	for idx := range sources {
+		sources[idx] = -1
+	}
+	baseOwn := make(map[string]interface{}, len(base))
+	for key, val := range base {
+		if _, ok := override[key]; ok {
+			continue
+		}
+		baseOwn[key] = val
+	}
+	fields0, err := genApplyMergedItemFromBase(baseOwn, obj)
+	if err != nil {
+		return nil, fields, sources, err
+	}
+	sources.merge(&fields, fields0, 0)
+	fields1, err := genApplyMergedItemFromOverride(override, obj)
+	if err != nil {
+		return nil, fields, sources, err
+	}
+	sources.merge(&fields, fields1, 1)
+	return obj, fields, sources, nil
+}
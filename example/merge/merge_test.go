@@ -0,0 +1,57 @@
+package merge
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/adyzng/gotool/m2s"
+)
+
+func TestMergeLayeredConfig(t *testing.T) {
+	file := map[string]string{"host": "10.0.0.1", "port": "9000"}
+	flags := map[string]interface{}{"port": float64(9090), "Debug": true}
+
+	obj, fields, sources, err := genMapToLayeredConfig(file, flags)
+	if err != nil {
+		t.Fatalf("merge failed. err=%v", err)
+	}
+	if obj.Host != "10.0.0.1" || obj.Port != 9090 || !obj.Debug || obj.TimeoutMs != 3000 {
+		t.Errorf("unexpected config. obj=%+v", obj)
+	}
+	if fields.Has(genLayeredConfigField_TimeoutMs) || !fields.Has(genLayeredConfigField_Host) {
+		t.Errorf("unexpected fields. fields=%v", fields.Names())
+	}
+	for f, want := range map[genLayeredConfigField]int{
+		genLayeredConfigField_Host:      0,
+		genLayeredConfigField_Port:      1,
+		genLayeredConfigField_Debug:     1,
+		genLayeredConfigField_TimeoutMs: -1,
+	} {
+		if got := sources.From(f); got != want {
+			t.Errorf("source of %v = %d, want %d", f, got, want)
+		}
+	}
+
+	if _, _, _, err = genMapToLayeredConfig(file, map[string]interface{}{"port": "abc"}); err == nil {
+		t.Errorf("wrong type in flags should fail")
+	}
+}
+
+func TestMergeRequired(t *testing.T) {
+	base := map[string]interface{}{"item_id": float64(1)}
+	override := map[string]interface{}{"title": "go", "item_id": float64(2)}
+
+	obj, _, sources, err := genMapToMergedItem(base, override)
+	if err != nil {
+		t.Fatalf("merge failed. err=%v", err)
+	}
+	if obj.ItemId != 2 || obj.Title != "go" || sources.From(genMergedItemField_ItemId) != 1 {
+		t.Errorf("unexpected item. obj=%+v", obj)
+	}
+
+	var ke *m2s.KeyError
+	_, _, _, err = genMapToMergedItem(base, map[string]interface{}{})
+	if !errors.As(err, &ke) || ke.Key != "title" || !errors.Is(err, m2s.ErrMissingKey) {
+		t.Errorf("missing title should fail. err=%v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package model
+
+// protoc-gen-go 生成代码的简化版本，内部字段用占位类型代替 protoimpl
+
+type BookStatus int32
+
+const (
+	BookStatus_BOOK_STATUS_UNKNOWN BookStatus = 0
+	BookStatus_BOOK_STATUS_ONLINE  BookStatus = 1
+	BookStatus_BOOK_STATUS_OFFLINE BookStatus = 2
+)
+
+// Enum value maps for BookStatus.
+var (
+	BookStatus_name = map[int32]string{
+		0: "BOOK_STATUS_UNKNOWN",
+		1: "BOOK_STATUS_ONLINE",
+		2: "BOOK_STATUS_OFFLINE",
+	}
+	BookStatus_value = map[string]int32{
+		"BOOK_STATUS_UNKNOWN": 0,
+		"BOOK_STATUS_ONLINE":  1,
+		"BOOK_STATUS_OFFLINE": 2,
+	}
+)
+
+type messageState struct{}
+
+type ProtoBook struct {
+	state         messageState
+	sizeCache     int32
+	unknownFields []byte
+
+	BookId   int64      `protobuf:"varint,1,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	Title    string     `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Status   BookStatus `protobuf:"varint,3,opt,name=status,proto3,enum=model.BookStatus" json:"status,omitempty"`
+	Rating   *float64   `protobuf:"fixed64,4,opt,name=rating,proto3,oneof" json:"rating,omitempty"`
+	AuthorId uint32     `protobuf:"varint,5,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	// Types that are assignable to Source:
+	//
+	//	*ProtoBook_Url
+	//	*ProtoBook_Isbn
+	//	*ProtoBook_Origin
+	Source isProtoBook_Source `protobuf_oneof:"source"`
+
+	XXX_unrecognized []byte `json:"-"`
+}
+
+type isProtoBook_Source interface {
+	isProtoBook_Source()
+}
+
+type ProtoBook_Url struct {
+	Url string `protobuf:"bytes,6,opt,name=url,proto3,oneof"`
+}
+
+type ProtoBook_Isbn struct {
+	Isbn int64 `protobuf:"varint,7,opt,name=isbn,proto3,oneof"`
+}
+
+type ProtoBook_Origin struct {
+	Origin BookStatus `protobuf:"varint,8,opt,name=origin,proto3,enum=model.BookStatus,oneof"`
+}
+
+func (*ProtoBook_Url) isProtoBook_Source() {}
+
+func (*ProtoBook_Isbn) isProtoBook_Source() {}
+
+func (*ProtoBook_Origin) isProtoBook_Source() {}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*ProtoBook) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*ProtoBook_Url)(nil),
+		(*ProtoBook_Isbn)(nil),
+		(*ProtoBook_Origin)(nil),
+	}
+}
+
+func (x *ProtoBook) GetSource() isProtoBook_Source {
+	if x != nil {
+		return x.Source
+	}
+	return nil
+}
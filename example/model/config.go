@@ -0,0 +1,66 @@
+package model
+
+import (
+	"time"
+)
+
+// ServerConfig 零值不可用，需要通过 NewServerConfig 设置默认值
+type ServerConfig struct {
+	Host      string `json:"host"`
+	Port      int    `json:"port"`
+	TimeoutMs int64  `json:"timeout_ms"`
+	Debug     bool
+}
+
+func NewServerConfig() *ServerConfig {
+	return &ServerConfig{
+		Host:      "127.0.0.1",
+		Port:      8080,
+		TimeoutMs: 3000,
+	}
+}
+
+// NewLocalServerConfig 本地调试用的默认配置
+func NewLocalServerConfig() *ServerConfig {
+	cfg := NewServerConfig()
+	cfg.Host = "localhost"
+	cfg.Debug = true
+	return cfg
+}
+
+// CacheConfig 通过 Default 方法设置默认值
+type CacheConfig struct {
+	Size       int    `json:"size"`
+	TTLSeconds int64  `json:"ttl"`
+	Policy     string `json:"policy"`
+}
+
+func (c *CacheConfig) Default() {
+	c.Size = 1024
+	c.TTLSeconds = 60
+	c.Policy = "lru"
+}
+
+// AppConfig 从环境变量加载: APP_NAME, APP_DB_HOST ...
+type AppConfig struct {
+	Name     string        `env:"NAME,required"`
+	Port     int32         `env:"PORT,default=8080"`
+	Debug    *bool         `env:"DEBUG"`
+	LogLevel string        `env:",default=info"`
+	Timeout  time.Duration `env:"TIMEOUT,default=3s"`
+	Status   BookStatus
+	Peers    []string
+	Secret   string `env:"-"`
+	Banner   string `env:"BANNER,allowempty,default=hello"`
+	DB       DBConfig
+	Cache    *CacheConfig
+	Server   *ServerConfig
+}
+
+type DBConfig struct {
+	Host     string `env:"HOST,default=127.0.0.1"`
+	Port     int    `env:"PORT,default=3306"`
+	User     string `env:"USER,required"`
+	Password string
+	MaxConns *int32
+}
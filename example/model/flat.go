@@ -0,0 +1,35 @@
+package model
+
+// FlatBook 从扁平的 map 加载（Redis hash），比如: author.name, publisher_city, chapters.0.title, tags.0
+type FlatBook struct {
+	Id        int64      `json:"id"`
+	Title     string     `json:"title"`
+	Author    Author     `json:"author" m2s:",prefix=author."`
+	Publisher *Publisher `json:"publisher" m2s:",prefix=publisher_"`
+	Chapters  []*Chapter `json:"chapters" m2s:",prefix=chapters."`
+	Tags      []string   `json:"tags" m2s:",prefix=tags."`
+	// 值为 JSON 字符串: labels = ["a","b"]
+	Labels []string          `json:"labels" m2s:",json"`
+	Ext    map[string]string `json:"ext" m2s:",json"`
+	Source *Author           `json:"source" m2s:",json"`
+}
+
+type Author struct {
+	Id   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type Publisher struct {
+	Name string `json:"name"`
+	City string `json:"city"`
+}
+
+func (p *Publisher) Default() {
+	p.City = "unknown"
+}
+
+type Chapter struct {
+	Title  string  `json:"title"`
+	Pages  int32   `json:"pages"`
+	Editor *Author `json:"editor"`
+}
@@ -0,0 +1,67 @@
+package model
+
+// kitex(thriftgo) 生成代码的简化版本
+
+type ItemStatus int64
+
+const (
+	ItemStatus_OFFLINE ItemStatus = 0
+	ItemStatus_ONLINE  ItemStatus = 1
+)
+
+type ApiItemInfo struct {
+	ItemId int64      `thrift:"item_id,1,required" frugal:"1,required,i64" json:"item_id"`
+	Title  string     `thrift:"title,2,required" frugal:"2,required,string" json:"name"`
+	Status ItemStatus `thrift:"status,3,optional" frugal:"3,optional,ItemStatus" json:"status,omitempty"`
+	Score  *float64   `thrift:"score,4,optional" frugal:"4,optional,double" json:"score,omitempty"`
+	Lang   string     `thrift:"lang,5,optional" frugal:"5,optional,string" json:"lang,omitempty"`
+}
+
+func NewApiItemInfo() *ApiItemInfo {
+	return &ApiItemInfo{
+		Status: ItemStatus_ONLINE,
+		Lang:   "zh",
+	}
+}
+
+func (p *ApiItemInfo) GetItemId() (v int64) {
+	return p.ItemId
+}
+
+func (p *ApiItemInfo) GetTitle() (v string) {
+	return p.Title
+}
+
+var ApiItemInfo_Status_DEFAULT ItemStatus = ItemStatus_ONLINE
+
+func (p *ApiItemInfo) GetStatus() (v ItemStatus) {
+	if !p.IsSetStatus() {
+		return ApiItemInfo_Status_DEFAULT
+	}
+	return p.Status
+}
+
+var ApiItemInfo_Score_DEFAULT float64
+
+func (p *ApiItemInfo) GetScore() (v float64) {
+	if !p.IsSetScore() {
+		return ApiItemInfo_Score_DEFAULT
+	}
+	return *p.Score
+}
+
+func (p *ApiItemInfo) SetItemId(val int64) {
+	p.ItemId = val
+}
+
+func (p *ApiItemInfo) SetTitle(val string) {
+	p.Title = val
+}
+
+func (p *ApiItemInfo) IsSetStatus() bool {
+	return p.Status != ApiItemInfo_Status_DEFAULT
+}
+
+func (p *ApiItemInfo) IsSetScore() bool {
+	return p.Score != nil
+}
@@ -8,6 +8,19 @@ const (
 	BookType_PAGE_RIGHT BookType = 3
 )
 
+// String 与 thrift 生成的枚举一样实现 fmt.Stringer
+func (p BookType) String() string {
+	switch p {
+	case BookType_STRIP:
+		return "STRIP"
+	case BookType_PAGE_LEFT:
+		return "PAGE_LEFT"
+	case BookType_PAGE_RIGHT:
+		return "PAGE_RIGHT"
+	}
+	return "<UNSET>"
+}
+
 type ApiBookInfo struct {
 	Id             int64     `json:"book_id"`
 	Name           string    `json:"book_name"`
@@ -20,3 +33,11 @@ type ApiBookInfo struct {
 	Category       *string   `json:"category,omitempty"`
 	IsFirstRead    bool      `json:"is_first_read,omitempty"`
 }
+
+// TaggedBook 列表字段的值为分隔符拼接的字符串，比如: author_ids = "1,2,3"
+type TaggedBook struct {
+	Id        int64      `json:"id"`
+	AuthorIds []int64    `json:"author_ids" m2s:",sep=,"`
+	Tags      []string   `json:"tags" m2s:",sep=|"`
+	Types     []BookType `json:"types" m2s:",sep=;"`
+}
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+)
+
+// BookRecord 数据库中的书籍记录
+type BookRecord struct {
+	Id        int64             `db:"id"`
+	Title     string            `db:"title"`
+	Price     *float64          `db:"price"`
+	Status    BookStatus        `db:"status"`
+	Hot       bool              `db:"is_hot"`
+	CreatedAt time.Time         `db:"created_at"`
+	DeletedAt *time.Time        `db:"deleted_at"`
+	Tags      []string          `db:"-"`
+	Meta      map[string]string `db:"meta"` // 扫描不支持的类型
+}
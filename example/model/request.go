@@ -0,0 +1,21 @@
+package model
+
+// SearchRequest 查询参数
+type SearchRequest struct {
+	Keyword  string       `form:"q"`
+	Page     int32        `form:"page"`
+	PageSize *int32       `query:"page_size"`
+	Tags     []string     `form:"tag"`
+	AuthorId []int64      `form:"author_id"`
+	Status   BookStatus   `form:"status"`
+	Statuses []BookStatus `form:"statuses"`
+	Levels   []ItemStatus `form:"level"`
+}
+
+// RequestMeta 请求头
+type RequestMeta struct {
+	TraceId string   `header:"x-trace-id"`
+	UserId  int64    `header:"x-user-id"`
+	Debug   *bool    `header:"x-debug"`
+	Accept  []string `header:"accept"`
+}
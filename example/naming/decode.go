@@ -0,0 +1,12 @@
+package naming
+
+import (
+	"github.com/adyzng/gotool/example/model"
+)
+
+//go:generate map2struct -match=^Decode(\w+)Stub -name=decode{{.Model}} -strict
+
+// DecodeItemStub 同一个包中的第二个生成器，生成 decodeApiItemInfo、decodeApplyApiItemInfo
+func DecodeItemStub(src map[string]interface{}) (*model.ApiItemInfo, error) {
+	return nil, nil
+}
@@ -0,0 +1,64 @@
+// Auto generated code, DO NOT EDIT.
+
+package naming
+
+import (
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+	"github.com/spf13/cast"
+)
+
+func decodeApiItemInfo(src map[string]interface{}) (obj *model.ApiItemInfo, err error) {
+	if _, ok := src["item_id"]; !ok {
+		return nil, &m2s.KeyError{Key: "item_id", Err: m2s.ErrMissingKey}
+	}
+	if _, ok := src["title"]; !ok {
+		return nil, &m2s.KeyError{Key: "title", Err: m2s.ErrMissingKey}
+	}
+	obj = model.NewApiItemInfo()
+	if err = decodeApplyApiItemInfo(src, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// decodeApplyApiItemInfo 只覆盖 src 中存在的字段
+func decodeApplyApiItemInfo(src map[string]interface{}, obj *model.ApiItemInfo) (err error) {
+	// 直接赋值的字段
+	if tmp, ok := src["item_id"]; ok {
+		if obj.ItemId, err = m2s.ToInt64E(tmp); err != nil {
+			return &m2s.KeyError{Key: "item_id", Err: err}
+		}
+	}
+	if tmp, ok := src["title"]; ok {
+		if obj.Title, err = cast.ToStringE(tmp); err != nil {
+			return &m2s.KeyError{Key: "title", Err: err}
+		}
+	}
+	if tmp, ok := src["lang"]; ok {
+		if obj.Lang, err = cast.ToStringE(tmp); err != nil {
+			return &m2s.KeyError{Key: "lang", Err: err}
+		}
+	}
+
+	// 枚举类型
+	if tmp, ok := src["status"]; ok {
+		num, err := m2s.ToInt64E(tmp)
+		if err != nil {
+			return &m2s.KeyError{Key: "status", Err: err}
+		}
+		val := (model.ItemStatus)(num)
+		obj.Status = val
+	}
+
+	// 带赋值表达式的（指针类型）
+	if tmp, ok := src["score"]; ok {
+		val, err := m2s.ToFloat64E(tmp)
+		if err != nil {
+			return &m2s.KeyError{Key: "score", Err: err}
+		}
+		obj.Score = &val
+	}
+
+	return err
+}
@@ -0,0 +1,12 @@
+package naming
+
+import (
+	"github.com/adyzng/gotool/example/model"
+)
+
+//go:generate map2struct -name={{.Name}}Gen -exported -presence
+
+// MapToBook 生成导出的 MapToBookGen、ApplyBookGen
+func MapToBook(src map[string]string) (*model.ApiBookInfo, error) {
+	return nil, nil
+}
@@ -0,0 +1,127 @@
+// Auto generated code, DO NOT EDIT.
+
+package naming
+
+import (
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+)
+
+// BookFieldGen 标识 model.ApiBookInfo 的字段
+type BookFieldGen uint
+
+const (
+	BookFieldGen_Id BookFieldGen = iota
+	BookFieldGen_Name
+	BookFieldGen_CopyrightInfo
+	BookFieldGen_CreateTime
+	BookFieldGen_SerialCount
+	BookFieldGen_ThumbUrl
+	BookFieldGen_BookType
+	BookFieldGen_LatestReadTime
+	BookFieldGen_Category
+	BookFieldGen_IsFirstRead
+)
+
+var BookFieldGenNames = [...]string{
+	"Id",
+	"Name",
+	"CopyrightInfo",
+	"CreateTime",
+	"SerialCount",
+	"ThumbUrl",
+	"BookType",
+	"LatestReadTime",
+	"Category",
+	"IsFirstRead",
+}
+
+func (f BookFieldGen) String() string {
+	return BookFieldGenNames[f]
+}
+
+// BookFieldsGen 记录 src 中存在的字段
+type BookFieldsGen [1]uint64
+
+func (fs *BookFieldsGen) set(f BookFieldGen) {
+	fs[f/64] |= 1 << (f % 64)
+}
+
+// Has 字段是否出现在 src 中
+func (fs BookFieldsGen) Has(f BookFieldGen) bool {
+	return fs[f/64]&(1<<(f%64)) != 0
+}
+
+// Names 出现在 src 中的字段名
+func (fs BookFieldsGen) Names() []string {
+	names := make([]string, 0, len(BookFieldGenNames))
+	for idx, name := range BookFieldGenNames {
+		if fs.Has(BookFieldGen(idx)) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func MapToBookGen(src map[string]string) (obj *model.ApiBookInfo, fields BookFieldsGen, err error) {
+	obj = &model.ApiBookInfo{}
+	if fields, err = ApplyBookGen(src, obj); err != nil {
+		return nil, fields, err
+	}
+	return obj, fields, nil
+}
+
+// ApplyBookGen 只覆盖 src 中存在的字段
+func ApplyBookGen(src map[string]string, obj *model.ApiBookInfo) (fields BookFieldsGen, err error) {
+	// 直接赋值的字段
+	if tmp, ok := src["book_id"]; ok {
+		obj.Id = m2s.ToInt64(tmp)
+		fields.set(BookFieldGen_Id)
+	}
+	if tmp, ok := src["book_name"]; ok {
+		obj.Name = tmp
+		fields.set(BookFieldGen_Name)
+	}
+	if tmp, ok := src["copyright_info"]; ok {
+		obj.CopyrightInfo = tmp
+		fields.set(BookFieldGen_CopyrightInfo)
+	}
+	if tmp, ok := src["create_time"]; ok {
+		obj.CreateTime = tmp
+		fields.set(BookFieldGen_CreateTime)
+	}
+	if tmp, ok := src["thumb_url"]; ok {
+		obj.ThumbUrl = tmp
+		fields.set(BookFieldGen_ThumbUrl)
+	}
+	if tmp, ok := src["is_first_read"]; ok {
+		obj.IsFirstRead = m2s.ToBool(tmp)
+		fields.set(BookFieldGen_IsFirstRead)
+	}
+
+	// 枚举类型
+	if tmp, ok := src["book_type"]; ok {
+		val := (model.BookType)(m2s.ToInt64(tmp))
+		obj.BookType = &val
+		fields.set(BookFieldGen_BookType)
+	}
+
+	// 带赋值表达式的（指针类型）
+	if tmp, ok := src["serial_count"]; ok {
+		val := m2s.ToInt32(tmp)
+		obj.SerialCount = &val
+		fields.set(BookFieldGen_SerialCount)
+	}
+	if tmp, ok := src["latest_read_time"]; ok {
+		val := m2s.ToInt64(tmp)
+		obj.LatestReadTime = &val
+		fields.set(BookFieldGen_LatestReadTime)
+	}
+	if tmp, ok := src["category"]; ok {
+		val := tmp
+		obj.Category = &val
+		fields.set(BookFieldGen_Category)
+	}
+
+	return fields, err
+}
@@ -0,0 +1,52 @@
+package naming
+
+import (
+	"testing"
+
+	"github.com/adyzng/gotool/example/model"
+)
+
+// TestNaming 同一个包中两个生成器的函数名不冲突
+func TestNaming(t *testing.T) {
+	book, fields, err := MapToBookGen(map[string]string{"book_id": "7", "book_name": "go"})
+	if err != nil {
+		t.Fatalf("convert book failed. err=%v", err)
+	}
+	if book.Id != 7 || book.Name != "go" || !fields.Has(BookFieldGen_Id) {
+		t.Errorf("unexpected book. obj=%+v fields=%v", book, fields.Names())
+	}
+
+	item, err := decodeApiItemInfo(map[string]interface{}{"item_id": float64(1), "title": "go"})
+	if err != nil {
+		t.Fatalf("convert item failed. err=%v", err)
+	}
+	if item.ItemId != 1 || item.Title != "go" {
+		t.Errorf("unexpected item. obj=%+v", item)
+	}
+}
+
+// TestNamingHelpers 辅助函数和类型也使用命名模板
+func TestNamingHelpers(t *testing.T) {
+	book := &model.ApiBookInfo{Id: 1, Name: "old"}
+	fields, err := ApplyBookGen(map[string]string{"book_name": "new"}, book)
+	if err != nil {
+		t.Fatalf("apply book failed. err=%v", err)
+	}
+	var want BookFieldsGen
+	want.set(BookFieldGen_Name)
+	if book.Id != 1 || book.Name != "new" || fields != want {
+		t.Errorf("unexpected book. obj=%+v fields=%v", book, fields.Names())
+	}
+
+	item := model.NewApiItemInfo()
+	if err = decodeApplyApiItemInfo(map[string]interface{}{"lang": "en"}, item); err != nil || item.Lang != "en" {
+		t.Errorf("unexpected item. obj=%+v err=%v", item, err)
+	}
+	// -strict 只作用于 decode.go 中的 go:generate
+	if _, err = decodeApiItemInfo(map[string]interface{}{"item_id": "abc", "title": "go"}); err == nil {
+		t.Errorf("strict decode should fail")
+	}
+	if _, _, err = MapToBookGen(map[string]string{"book_id": "abc"}); err != nil {
+		t.Errorf("non-strict convert should ignore errors. err=%v", err)
+	}
+}
@@ -0,0 +1,12 @@
+package patch
+
+import (
+	"github.com/adyzng/gotool/example/model"
+)
+
+//go:generate map2struct -strict -presence -unknown=error -slice
+
+// MapToBookPatch 部分更新，需要知道哪些字段来自 src
+func MapToBookPatch(src map[string]string) (*model.ApiBookInfo, error) {
+	return nil, nil
+}
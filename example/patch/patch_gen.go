@@ -0,0 +1,175 @@
+// Auto generated code, DO NOT EDIT.
+
+package patch
+
+import (
+	"sort"
+
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+)
+
+// genBookPatchField 标识 model.ApiBookInfo 的字段
+type genBookPatchField uint
+
+const (
+	genBookPatchField_Id genBookPatchField = iota
+	genBookPatchField_Name
+	genBookPatchField_CopyrightInfo
+	genBookPatchField_CreateTime
+	genBookPatchField_SerialCount
+	genBookPatchField_ThumbUrl
+	genBookPatchField_BookType
+	genBookPatchField_LatestReadTime
+	genBookPatchField_Category
+	genBookPatchField_IsFirstRead
+)
+
+var genBookPatchFieldNames = [...]string{
+	"Id",
+	"Name",
+	"CopyrightInfo",
+	"CreateTime",
+	"SerialCount",
+	"ThumbUrl",
+	"BookType",
+	"LatestReadTime",
+	"Category",
+	"IsFirstRead",
+}
+
+func (f genBookPatchField) String() string {
+	return genBookPatchFieldNames[f]
+}
+
+// genBookPatchFields 记录 src 中存在的字段
+type genBookPatchFields [1]uint64
+
+func (fs *genBookPatchFields) set(f genBookPatchField) {
+	fs[f/64] |= 1 << (f % 64)
+}
+
+// Has 字段是否出现在 src 中
+func (fs genBookPatchFields) Has(f genBookPatchField) bool {
+	return fs[f/64]&(1<<(f%64)) != 0
+}
+
+// Names 出现在 src 中的字段名
+func (fs genBookPatchFields) Names() []string {
+	names := make([]string, 0, len(genBookPatchFieldNames))
+	for idx, name := range genBookPatchFieldNames {
+		if fs.Has(genBookPatchField(idx)) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func genMapToBookPatch(src map[string]string) (obj *model.ApiBookInfo, fields genBookPatchFields, err error) {
+	obj = &model.ApiBookInfo{}
+	if fields, err = genApplyBookPatch(src, obj); err != nil {
+		return nil, fields, err
+	}
+	return obj, fields, nil
+}
+
+// genApplyBookPatch 只覆盖 src 中存在的字段
+func genApplyBookPatch(src map[string]string, obj *model.ApiBookInfo) (fields genBookPatchFields, err error) {
+	// 检查未知的 key
+	var unknown []string
+	for key := range src {
+		switch key {
+		case "book_id", "book_name", "copyright_info", "create_time", "serial_count", "thumb_url", "book_type", "latest_read_time", "category", "is_first_read":
+		default:
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	if len(unknown) > 0 {
+		return fields, &m2s.UnknownKeyError{Keys: unknown}
+	}
+
+	// 直接赋值的字段
+	if tmp, ok := src["book_id"]; ok {
+		if obj.Id, err = m2s.ToInt64E(tmp); err != nil {
+			return fields, &m2s.KeyError{Key: "book_id", Err: err}
+		}
+		fields.set(genBookPatchField_Id)
+	}
+	if tmp, ok := src["book_name"]; ok {
+		obj.Name = tmp
+		fields.set(genBookPatchField_Name)
+	}
+	if tmp, ok := src["copyright_info"]; ok {
+		obj.CopyrightInfo = tmp
+		fields.set(genBookPatchField_CopyrightInfo)
+	}
+	if tmp, ok := src["create_time"]; ok {
+		obj.CreateTime = tmp
+		fields.set(genBookPatchField_CreateTime)
+	}
+	if tmp, ok := src["thumb_url"]; ok {
+		obj.ThumbUrl = tmp
+		fields.set(genBookPatchField_ThumbUrl)
+	}
+	if tmp, ok := src["is_first_read"]; ok {
+		if obj.IsFirstRead, err = m2s.ToBoolE(tmp); err != nil {
+			return fields, &m2s.KeyError{Key: "is_first_read", Err: err}
+		}
+		fields.set(genBookPatchField_IsFirstRead)
+	}
+
+	// 枚举类型
+	if tmp, ok := src["book_type"]; ok {
+		num, err := m2s.ToInt64E(tmp)
+		if err != nil {
+			return fields, &m2s.KeyError{Key: "book_type", Err: err}
+		}
+		val := (model.BookType)(num)
+		obj.BookType = &val
+		fields.set(genBookPatchField_BookType)
+	}
+
+	// 带赋值表达式的（指针类型）
+	if tmp, ok := src["serial_count"]; ok {
+		val, err := m2s.ToInt32E(tmp)
+		if err != nil {
+			return fields, &m2s.KeyError{Key: "serial_count", Err: err}
+		}
+		obj.SerialCount = &val
+		fields.set(genBookPatchField_SerialCount)
+	}
+	if tmp, ok := src["latest_read_time"]; ok {
+		val, err := m2s.ToInt64E(tmp)
+		if err != nil {
+			return fields, &m2s.KeyError{Key: "latest_read_time", Err: err}
+		}
+		obj.LatestReadTime = &val
+		fields.set(genBookPatchField_LatestReadTime)
+	}
+	if tmp, ok := src["category"]; ok {
+		val := tmp
+		obj.Category = &val
+		fields.set(genBookPatchField_Category)
+	}
+
+	return fields, err
+}
+
+// genMapToBookPatchSlice 按顺序批量转换，失败时返回 *m2s.RowError
+func genMapToBookPatchSlice(srcs []map[string]string) ([]*model.ApiBookInfo, error) {
+	return genMapToBookPatchSliceParallel(srcs, 1)
+}
+
+// genMapToBookPatchSliceParallel 最多 workers 个 goroutine 并发转换，结果顺序与 srcs 一致；workers <= 1 时顺序转换
+func genMapToBookPatchSliceParallel(srcs []map[string]string, workers int) ([]*model.ApiBookInfo, error) {
+	list := make([]*model.ApiBookInfo, len(srcs))
+	err := m2s.ForEach(len(srcs), workers, func(idx int) (err error) {
+		list[idx], _, err = genMapToBookPatch(srcs[idx])
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
@@ -0,0 +1,49 @@
+package patch
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+)
+
+func TestApplyBookPatch(t *testing.T) {
+	category := "novel"
+	obj := &model.ApiBookInfo{Id: 1, Name: "old", ThumbUrl: "a.png", Category: &category}
+
+	fields, err := genApplyBookPatch(map[string]string{"book_name": "new", "serial_count": "3", "book_type": "2"}, obj)
+	if err != nil {
+		t.Fatalf("apply failed. err=%v", err)
+	}
+	if obj.Id != 1 || obj.Name != "new" || obj.ThumbUrl != "a.png" || obj.Category != &category {
+		t.Errorf("untouched fields changed. obj=%+v", obj)
+	}
+	if obj.SerialCount == nil || *obj.SerialCount != 3 || obj.BookType == nil || *obj.BookType != model.BookType(2) {
+		t.Errorf("pointer fields not set. obj=%+v", obj)
+	}
+	if got, want := fields.Names(), []string{"Name", "SerialCount", "BookType"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("fields = %v, want %v", got, want)
+	}
+	if fields.Has(genBookPatchField_Id) || !fields.Has(genBookPatchField_Name) {
+		t.Errorf("unexpected presence. fields=%v", fields.Names())
+	}
+}
+
+func TestMapToBookPatchErrors(t *testing.T) {
+	var unknownErr *m2s.UnknownKeyError
+	_, _, err := genMapToBookPatch(map[string]string{"book_id": "1", "zzz": "x", "aaa": "y"})
+	if !errors.As(err, &unknownErr) || !reflect.DeepEqual(unknownErr.Keys, []string{"aaa", "zzz"}) {
+		t.Errorf("want sorted unknown keys. err=%v", err)
+	}
+
+	var keyErr *m2s.KeyError
+	obj, fields, err := genMapToBookPatch(map[string]string{"book_name": "go", "serial_count": "abc"})
+	if !errors.As(err, &keyErr) || keyErr.Key != "serial_count" || obj != nil {
+		t.Errorf("want serial_count key error. obj=%+v err=%v", obj, err)
+	}
+	if !fields.Has(genBookPatchField_Name) || fields.Has(genBookPatchField_SerialCount) {
+		t.Errorf("fields should stop at the failed key. fields=%v", fields.Names())
+	}
+}
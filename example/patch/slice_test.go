@@ -0,0 +1,52 @@
+package patch
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/adyzng/gotool/m2s"
+)
+
+func TestMapToBookPatchSlice(t *testing.T) {
+	srcs := make([]map[string]string, 100)
+	for idx := range srcs {
+		srcs[idx] = map[string]string{"book_id": strconv.Itoa(idx), "book_name": "go"}
+	}
+	for _, workers := range []int{1, 8} {
+		list, err := genMapToBookPatchSliceParallel(srcs, workers)
+		if err != nil {
+			t.Fatalf("workers=%d err=%v", workers, err)
+		}
+		for idx, obj := range list {
+			if obj.Id != int64(idx) || obj.Name != "go" {
+				t.Errorf("workers=%d list[%d] = %+v", workers, idx, obj)
+			}
+		}
+	}
+
+	srcs[42] = map[string]string{"book_id": "abc"}
+	srcs[60] = map[string]string{"unknown": "x"}
+	var rowErr *m2s.RowError
+	var keyErr *m2s.KeyError
+	if _, err := genMapToBookPatchSlice(srcs); !errors.As(err, &rowErr) || rowErr.Row != 42 || !errors.As(err, &keyErr) {
+		t.Errorf("want row 42 key error. err=%v", err)
+	}
+	if _, err := genMapToBookPatchSliceParallel(srcs, 8); !errors.As(err, &rowErr) || rowErr.Row != 42 {
+		t.Errorf("want row 42 error. err=%v", err)
+	}
+}
+
+func BenchmarkGenMapToBookPatchSliceParallel(b *testing.B) {
+	srcs := make([]map[string]string, 1000)
+	for idx := range srcs {
+		srcs[idx] = map[string]string{"book_id": strconv.Itoa(idx), "book_name": "go", "serial_count": "3"}
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := genMapToBookPatchSliceParallel(srcs, 4); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package proto
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+)
+
+// TestDecodeDiff 生成代码与 m2s.Decode 的结果必须一致
+func TestDecodeDiff(t *testing.T) {
+	m2s.RegisterEnum(model.BookStatus(0), model.BookStatus_value)
+
+	cases := []map[string]interface{}{
+		{},
+		{"bookId": json.Number("9007199254740993"), "title": "go", "authorId": float64(7)},
+		{"status": "BOOK_STATUS_ONLINE", "rating": float64(4.5), "url": "https://example.com"},
+		{"status": float64(2), "isbn": json.Number("9787111")},
+		{"origin": "BOOK_STATUS_OFFLINE", "book_id": "unknown key"},
+		{"bookId": float64(1.5)},
+		{"authorId": float64(-1)},
+		{"status": "NOT_A_STATUS"},
+		{"rating": "abc"},
+	}
+	for _, src := range cases {
+		want, _, wantErr := genMapToProtoBook(src)
+
+		got := &model.ProtoBook{}
+		gotErr := m2s.Decode(src, got, m2s.Strict())
+		if (wantErr != nil) != (gotErr != nil) {
+			t.Errorf("src=%v gen err=%v, decode err=%v", src, wantErr, gotErr)
+			continue
+		}
+		if wantErr == nil && !reflect.DeepEqual(want, got) {
+			t.Errorf("src=%v gen=%+v, decode=%+v", src, want, got)
+		}
+	}
+}
+
+// TestDecodeRegistered -register 生成的 init 注册了转换函数
+func TestDecodeRegistered(t *testing.T) {
+	src := map[string]interface{}{"bookId": float64(7), "title": "go", "status": "BOOK_STATUS_ONLINE"}
+	want, _, _ := genMapToProtoBook(src)
+
+	obj, err := m2s.DecodeNew(src, (*model.ProtoBook)(nil))
+	if err != nil {
+		t.Fatalf("decode new failed. err=%v", err)
+	}
+	if !reflect.DeepEqual(want, obj) {
+		t.Errorf("decode new. want=%+v got=%+v", want, obj)
+	}
+
+	got := &model.ProtoBook{}
+	if err = m2s.DecodeInto(src, got); err != nil {
+		t.Fatalf("decode into failed. err=%v", err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("decode into. want=%+v got=%+v", want, got)
+	}
+
+	if err = m2s.DecodeInto(map[string]interface{}{"rating": "abc"}, got); err == nil {
+		t.Errorf("decode into should fail")
+	}
+}
@@ -0,0 +1,13 @@
+package proto
+
+import (
+	"github.com/adyzng/gotool/example/model"
+)
+
+//go:generate map2struct -strict -presence -register
+
+// MapToProtoBook protoc-gen-go 生成的结构体：跳过内部字段，key 取 protobuf tag 的 json= 部分，
+// 枚举支持按名字转换，oneof 通过包装类型赋值
+func MapToProtoBook(src map[string]interface{}) (*model.ProtoBook, error) {
+	return nil, nil
+}
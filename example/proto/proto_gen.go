@@ -0,0 +1,158 @@
+// Auto generated code, DO NOT EDIT.
+
+package proto
+
+import (
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+	"github.com/spf13/cast"
+)
+
+// genProtoBookField 标识 model.ProtoBook 的字段
+type genProtoBookField uint
+
+const (
+	genProtoBookField_BookId genProtoBookField = iota
+	genProtoBookField_Title
+	genProtoBookField_Status
+	genProtoBookField_Rating
+	genProtoBookField_AuthorId
+	genProtoBookField_Url
+	genProtoBookField_Isbn
+	genProtoBookField_Origin
+)
+
+var genProtoBookFieldNames = [...]string{
+	"BookId",
+	"Title",
+	"Status",
+	"Rating",
+	"AuthorId",
+	"Source",
+	"Source",
+	"Source",
+}
+
+func (f genProtoBookField) String() string {
+	return genProtoBookFieldNames[f]
+}
+
+// genProtoBookFields 记录 src 中存在的字段
+type genProtoBookFields [1]uint64
+
+func (fs *genProtoBookFields) set(f genProtoBookField) {
+	fs[f/64] |= 1 << (f % 64)
+}
+
+// Has 字段是否出现在 src 中
+func (fs genProtoBookFields) Has(f genProtoBookField) bool {
+	return fs[f/64]&(1<<(f%64)) != 0
+}
+
+// Names 出现在 src 中的字段名
+func (fs genProtoBookFields) Names() []string {
+	names := make([]string, 0, len(genProtoBookFieldNames))
+	for idx, name := range genProtoBookFieldNames {
+		if fs.Has(genProtoBookField(idx)) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func genMapToProtoBook(src map[string]interface{}) (obj *model.ProtoBook, fields genProtoBookFields, err error) {
+	obj = &model.ProtoBook{}
+	if fields, err = genApplyProtoBook(src, obj); err != nil {
+		return nil, fields, err
+	}
+	return obj, fields, nil
+}
+
+// genApplyProtoBook 只覆盖 src 中存在的字段
+func genApplyProtoBook(src map[string]interface{}, obj *model.ProtoBook) (fields genProtoBookFields, err error) {
+	// 直接赋值的字段
+	if tmp, ok := src["bookId"]; ok {
+		if obj.BookId, err = m2s.ToInt64E(tmp); err != nil {
+			return fields, &m2s.KeyError{Key: "bookId", Err: err}
+		}
+		fields.set(genProtoBookField_BookId)
+	}
+	if tmp, ok := src["title"]; ok {
+		if obj.Title, err = cast.ToStringE(tmp); err != nil {
+			return fields, &m2s.KeyError{Key: "title", Err: err}
+		}
+		fields.set(genProtoBookField_Title)
+	}
+	if tmp, ok := src["authorId"]; ok {
+		if obj.AuthorId, err = m2s.ToUint32E(tmp); err != nil {
+			return fields, &m2s.KeyError{Key: "authorId", Err: err}
+		}
+		fields.set(genProtoBookField_AuthorId)
+	}
+
+	// 枚举类型
+	if tmp, ok := src["status"]; ok {
+		num, err := m2s.ToEnumE(tmp, model.BookStatus_value)
+		if err != nil {
+			return fields, &m2s.KeyError{Key: "status", Err: err}
+		}
+		val := (model.BookStatus)(num)
+		obj.Status = val
+		fields.set(genProtoBookField_Status)
+	}
+
+	// 带赋值表达式的（指针类型）
+	if tmp, ok := src["rating"]; ok {
+		val, err := m2s.ToFloat64E(tmp)
+		if err != nil {
+			return fields, &m2s.KeyError{Key: "rating", Err: err}
+		}
+		obj.Rating = &val
+		fields.set(genProtoBookField_Rating)
+	}
+
+	// proto oneof 字段
+	if tmp, ok := src["url"]; ok {
+		val, err := cast.ToStringE(tmp)
+		if err != nil {
+			return fields, &m2s.KeyError{Key: "url", Err: err}
+		}
+		obj.Source = &model.ProtoBook_Url{Url: val}
+		fields.set(genProtoBookField_Url)
+	}
+	if tmp, ok := src["isbn"]; ok {
+		val, err := m2s.ToInt64E(tmp)
+		if err != nil {
+			return fields, &m2s.KeyError{Key: "isbn", Err: err}
+		}
+		obj.Source = &model.ProtoBook_Isbn{Isbn: val}
+		fields.set(genProtoBookField_Isbn)
+	}
+	if tmp, ok := src["origin"]; ok {
+		num, err := m2s.ToEnumE(tmp, model.BookStatus_value)
+		if err != nil {
+			return fields, &m2s.KeyError{Key: "origin", Err: err}
+		}
+		val := (model.BookStatus)(num)
+		obj.Source = &model.ProtoBook_Origin{Origin: val}
+		fields.set(genProtoBookField_Origin)
+	}
+
+	return fields, err
+}
+
+func init() {
+	m2s.Register((map[string]interface{})(nil), (*model.ProtoBook)(nil), &m2s.Converter{
+		MapTo: func(src interface{}) (interface{}, error) {
+			obj, _, err := genMapToProtoBook(src.(map[string]interface{}))
+			if err != nil {
+				return nil, err
+			}
+			return obj, nil
+		},
+		Apply: func(src interface{}, obj interface{}) error {
+			_, err := genApplyProtoBook(src.(map[string]interface{}), obj.(*model.ProtoBook))
+			return err
+		},
+	})
+}
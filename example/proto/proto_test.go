@@ -0,0 +1,59 @@
+package proto
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+)
+
+func TestMapToProtoBook(t *testing.T) {
+	src := map[string]interface{}{"bookId": float64(7), "title": "go", "authorId": float64(3), "status": "BOOK_STATUS_ONLINE", "rating": 4.5}
+	obj, fields, err := genMapToProtoBook(src)
+	if err != nil {
+		t.Fatalf("convert failed. err=%v", err)
+	}
+	if obj.BookId != 7 || obj.Title != "go" || obj.AuthorId != 3 || obj.Rating == nil || *obj.Rating != 4.5 {
+		t.Errorf("unexpected book. obj=%+v", obj)
+	}
+	// 枚举按名字转换
+	if obj.Status != model.BookStatus_BOOK_STATUS_ONLINE {
+		t.Errorf("status = %v", obj.Status)
+	}
+	if fields.Has(genProtoBookField_Url) || !fields.Has(genProtoBookField_Status) {
+		t.Errorf("unexpected fields. fields=%v", fields.Names())
+	}
+
+	// key 取 protobuf tag 的 json=，name= 不识别
+	if obj, _, err = genMapToProtoBook(map[string]interface{}{"book_id": float64(7)}); err != nil || obj.BookId != 0 {
+		t.Errorf("book_id should be ignored. obj=%+v err=%v", obj, err)
+	}
+}
+
+func TestMapToProtoBookOneof(t *testing.T) {
+	cases := []struct {
+		src  map[string]interface{}
+		want model.ProtoBook
+	}{
+		{map[string]interface{}{"url": "https://example.com"}, model.ProtoBook{Source: &model.ProtoBook_Url{Url: "https://example.com"}}},
+		{map[string]interface{}{"isbn": float64(9787111)}, model.ProtoBook{Source: &model.ProtoBook_Isbn{Isbn: 9787111}}},
+		{map[string]interface{}{"origin": float64(2)}, model.ProtoBook{Source: &model.ProtoBook_Origin{Origin: model.BookStatus(2)}}},
+	}
+	for _, c := range cases {
+		obj, _, err := genMapToProtoBook(c.src)
+		if err != nil {
+			t.Errorf("src=%v err=%v", c.src, err)
+			continue
+		}
+		if !reflect.DeepEqual(obj.Source, c.want.Source) {
+			t.Errorf("src=%v source=%#v, want %#v", c.src, obj.Source, c.want.Source)
+		}
+	}
+
+	var keyErr *m2s.KeyError
+	if _, _, err := genMapToProtoBook(map[string]interface{}{"origin": "NOT_A_STATUS"}); !errors.As(err, &keyErr) || keyErr.Key != "origin" {
+		t.Errorf("want origin key error. err=%v", err)
+	}
+}
@@ -0,0 +1,12 @@
+package settings
+
+import (
+	"github.com/adyzng/gotool/example/model"
+)
+
+//go:generate map2struct
+
+// MapToServer 生成参数来自 .map2struct.yaml
+func MapToServer(src map[string]interface{}) (*model.ServerConfig, error) {
+	return nil, nil
+}
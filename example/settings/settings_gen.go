@@ -0,0 +1,92 @@
+// Auto generated code, DO NOT EDIT.
+
+package settings
+
+import (
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+	"github.com/spf13/cast"
+)
+
+// decodeServerField 标识 model.ServerConfig 的字段
+type decodeServerField uint
+
+const (
+	decodeServerField_Host decodeServerField = iota
+	decodeServerField_Port
+	decodeServerField_TimeoutMs
+	decodeServerField_Debug
+)
+
+var decodeServerFieldNames = [...]string{
+	"Host",
+	"Port",
+	"TimeoutMs",
+	"Debug",
+}
+
+func (f decodeServerField) String() string {
+	return decodeServerFieldNames[f]
+}
+
+// decodeServerFields 记录 src 中存在的字段
+type decodeServerFields [1]uint64
+
+func (fs *decodeServerFields) set(f decodeServerField) {
+	fs[f/64] |= 1 << (f % 64)
+}
+
+// Has 字段是否出现在 src 中
+func (fs decodeServerFields) Has(f decodeServerField) bool {
+	return fs[f/64]&(1<<(f%64)) != 0
+}
+
+// Names 出现在 src 中的字段名
+func (fs decodeServerFields) Names() []string {
+	names := make([]string, 0, len(decodeServerFieldNames))
+	for idx, name := range decodeServerFieldNames {
+		if fs.Has(decodeServerField(idx)) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func decodeServer(src map[string]interface{}) (obj *model.ServerConfig, fields decodeServerFields, err error) {
+	obj = model.NewServerConfig()
+	if fields, err = decodeApplyServer(src, obj); err != nil {
+		return nil, fields, err
+	}
+	return obj, fields, nil
+}
+
+// decodeApplyServer 只覆盖 src 中存在的字段
+func decodeApplyServer(src map[string]interface{}, obj *model.ServerConfig) (fields decodeServerFields, err error) {
+	// 直接赋值的字段
+	if tmp, ok := src["host"]; ok {
+		if obj.Host, err = cast.ToStringE(tmp); err != nil {
+			return fields, &m2s.KeyError{Key: "host", Err: err}
+		}
+		fields.set(decodeServerField_Host)
+	}
+	if tmp, ok := src["port"]; ok {
+		if obj.Port, err = m2s.ToIntE(tmp); err != nil {
+			return fields, &m2s.KeyError{Key: "port", Err: err}
+		}
+		fields.set(decodeServerField_Port)
+	}
+	if tmp, ok := src["timeout_ms"]; ok {
+		if obj.TimeoutMs, err = m2s.ToInt64E(tmp); err != nil {
+			return fields, &m2s.KeyError{Key: "timeout_ms", Err: err}
+		}
+		fields.set(decodeServerField_TimeoutMs)
+	}
+	if tmp, ok := src["Debug"]; ok {
+		if obj.Debug, err = m2s.ToBoolE(tmp); err != nil {
+			return fields, &m2s.KeyError{Key: "Debug", Err: err}
+		}
+		fields.set(decodeServerField_Debug)
+	}
+
+	return fields, err
+}
@@ -0,0 +1,20 @@
+package settings
+
+import (
+	"testing"
+)
+
+func TestSettings(t *testing.T) {
+	obj, fields, err := decodeServer(map[string]interface{}{"host": "10.0.0.1", "port": float64(9000)})
+	if err != nil {
+		t.Fatalf("convert failed. err=%v", err)
+	}
+	if obj.Host != "10.0.0.1" || obj.Port != 9000 || !fields.Has(decodeServerField_Port) {
+		t.Errorf("unexpected config. obj=%+v fields=%v", obj, fields.Names())
+	}
+
+	// 配置文件中的 strict
+	if _, _, err = decodeServer(map[string]interface{}{"port": "abc"}); err == nil {
+		t.Errorf("wrong type should fail")
+	}
+}
@@ -0,0 +1,19 @@
+package sqlscan
+
+import (
+	"database/sql"
+
+	"github.com/adyzng/gotool/example/model"
+)
+
+//go:generate map2struct
+
+// ScanBookRecord 按列名扫描查询结果
+func ScanBookRecord(rows *sql.Rows) ([]*model.BookRecord, error) {
+	return nil, nil
+}
+
+// ScanBookRecordRow 扫描单行，列顺序与 genBookRecordColumns 一致
+func ScanBookRecordRow(row *sql.Row) (*model.BookRecord, error) {
+	return nil, nil
+}
@@ -0,0 +1,156 @@
+// Auto generated code, DO NOT EDIT.
+
+package sqlscan
+
+import (
+	"database/sql"
+
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+	"github.com/spf13/cast"
+)
+
+// genBookRecordColumns model.BookRecord 对应的列，按字段顺序
+var genBookRecordColumns = []string{
+	"id",
+	"title",
+	"price",
+	"status",
+	"is_hot",
+	"created_at",
+	"deleted_at",
+}
+
+// genBookRecordColumnIndex 列对应的字段编号，未知的列返回 -1
+func genBookRecordColumnIndex(column string) int {
+	switch column {
+	case "id":
+		return 0
+	case "title":
+		return 1
+	case "price":
+		return 2
+	case "status":
+		return 3
+	case "is_hot":
+		return 4
+	case "created_at":
+		return 5
+	case "deleted_at":
+		return 6
+	}
+	return -1
+}
+
+// genSetBookRecordField 把列的值赋给字段，NULL 不赋值
+func genSetBookRecordField(obj *model.BookRecord, field int, tmp interface{}) (err error) {
+	if tmp == nil {
+		return nil
+	}
+	if b, ok := tmp.([]byte); ok {
+		tmp = string(b)
+	}
+	switch field {
+	case 0:
+		if obj.Id, err = m2s.ToInt64E(tmp); err != nil {
+			return &m2s.KeyError{Key: "id", Err: err}
+		}
+	case 1:
+		if obj.Title, err = cast.ToStringE(tmp); err != nil {
+			return &m2s.KeyError{Key: "title", Err: err}
+		}
+	case 2:
+		val, err := m2s.ToFloat64E(tmp)
+		if err != nil {
+			return &m2s.KeyError{Key: "price", Err: err}
+		}
+		obj.Price = &val
+	case 3:
+		num, err := m2s.ToEnumE(tmp, model.BookStatus_value)
+		if err != nil {
+			return &m2s.KeyError{Key: "status", Err: err}
+		}
+		val := (model.BookStatus)(num)
+		obj.Status = val
+	case 4:
+		if obj.Hot, err = m2s.ToBoolE(tmp); err != nil {
+			return &m2s.KeyError{Key: "is_hot", Err: err}
+		}
+	case 5:
+		if obj.CreatedAt, err = cast.ToTimeE(tmp); err != nil {
+			return &m2s.KeyError{Key: "created_at", Err: err}
+		}
+	case 6:
+		val, err := cast.ToTimeE(tmp)
+		if err != nil {
+			return &m2s.KeyError{Key: "deleted_at", Err: err}
+		}
+		obj.DeletedAt = &val
+	}
+
+	// 需要手动处理的字段
+	// obj.Meta = ? (meta)
+	return nil
+}
+
+// genScanBookRecord 按列名扫描所有行后关闭 rows，列与字段的对应关系只计算一次；未知的列返回 *m2s.UnknownKeyError
+func genScanBookRecord(rows *sql.Rows) (list []*model.BookRecord, err error) {
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	fields := make([]int, len(columns))
+	var unknown []string
+	for idx, column := range columns {
+		if fields[idx] = genBookRecordColumnIndex(column); fields[idx] >= 0 {
+			continue
+		}
+		switch column {
+		case "meta":
+			return nil, &m2s.KeyError{Key: column, Err: m2s.ErrUnsupportedField}
+		}
+		unknown = append(unknown, column)
+	}
+	if len(unknown) > 0 {
+		return nil, &m2s.UnknownKeyError{Keys: unknown}
+	}
+
+	vals := make([]interface{}, len(columns))
+	ptrs := make([]interface{}, len(columns))
+	for idx := range vals {
+		ptrs[idx] = &vals[idx]
+	}
+	for rows.Next() {
+		if err = rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+		obj := &model.BookRecord{}
+		for idx, val := range vals {
+			if err = genSetBookRecordField(obj, fields[idx], val); err != nil {
+				return nil, err
+			}
+		}
+		list = append(list, obj)
+	}
+	return list, rows.Err()
+}
+
+// genScanBookRecordRow 扫描单行；*sql.Row 无法获取列名，SELECT 的列必须与 genBookRecordColumns 的顺序一致
+func genScanBookRecordRow(row *sql.Row) (obj *model.BookRecord, err error) {
+	vals := make([]interface{}, len(genBookRecordColumns))
+	ptrs := make([]interface{}, len(vals))
+	for idx := range vals {
+		ptrs[idx] = &vals[idx]
+	}
+	if err = row.Scan(ptrs...); err != nil {
+		return nil, err
+	}
+	obj = &model.BookRecord{}
+	for idx, val := range vals {
+		if err = genSetBookRecordField(obj, idx, val); err != nil {
+			return nil, err
+		}
+	}
+	return obj, nil
+}
@@ -0,0 +1,153 @@
+package sqlscan
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+)
+
+// fakeDriver 返回固定结果的驱动，查询语句为结果集的名字
+type fakeDriver struct{}
+
+type fakeResult struct {
+	columns []string
+	values  [][]driver.Value
+	closed  bool
+}
+
+var fakeResults = map[string]*fakeResult{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) { return fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{query: query}, nil }
+func (fakeConn) Close() error                              { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+type fakeStmt struct{ query string }
+
+func (fakeStmt) Close() error  { return nil }
+func (fakeStmt) NumInput() int { return 0 }
+func (fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &fakeRows{result: fakeResults[s.query]}, nil
+}
+
+type fakeRows struct {
+	result *fakeResult
+	next   int
+}
+
+func (r *fakeRows) Columns() []string { return r.result.columns }
+func (r *fakeRows) Close() error      { r.result.closed = true; return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.result.values) {
+		return io.EOF
+	}
+	copy(dest, r.result.values[r.next])
+	r.next++
+	return nil
+}
+
+func init() {
+	sql.Register("m2sfake", fakeDriver{})
+}
+
+func TestScanBookRecord(t *testing.T) {
+	db, err := sql.Open("m2sfake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	fakeResults["books"] = &fakeResult{
+		// 列顺序与字段顺序不同
+		columns: []string{"title", "id", "status", "price", "created_at", "deleted_at", "is_hot"},
+		values: [][]driver.Value{
+			{[]byte("go"), int64(1), "BOOK_STATUS_ONLINE", 9.5, now, nil, int64(1)},
+			{"rust", int64(2), int64(2), nil, now, now, false},
+		},
+	}
+	rows, err := db.Query("books")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	list, err := genScanBookRecord(rows)
+	if err != nil {
+		t.Fatalf("scan failed. err=%v", err)
+	}
+	price := 9.5
+	want := []*model.BookRecord{
+		{Id: 1, Title: "go", Price: &price, Status: model.BookStatus_BOOK_STATUS_ONLINE, Hot: true, CreatedAt: now},
+		{Id: 2, Title: "rust", Status: model.BookStatus(2), CreatedAt: now, DeletedAt: &now},
+	}
+	if !reflect.DeepEqual(list, want) {
+		t.Errorf("list = %+v, want %+v", list, want)
+	}
+
+	// 出错时生成的函数关闭 rows
+	fakeResults["unknown"] = &fakeResult{columns: []string{"id", "author"}}
+	rows, err = db.Query("unknown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var unknownErr *m2s.UnknownKeyError
+	if _, err = genScanBookRecord(rows); !errors.As(err, &unknownErr) || unknownErr.Keys[0] != "author" {
+		t.Errorf("want unknown column error. err=%v", err)
+	}
+	if !fakeResults["unknown"].closed {
+		t.Errorf("rows not closed")
+	}
+
+	// 字段类型不支持的列不是未知的列
+	fakeResults["meta"] = &fakeResult{columns: []string{"id", "meta"}}
+	rows, err = db.Query("meta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keyErr *m2s.KeyError
+	if _, err = genScanBookRecord(rows); !errors.As(err, &keyErr) || keyErr.Key != "meta" || !errors.Is(err, m2s.ErrUnsupportedField) {
+		t.Errorf("want unsupported field error. err=%v", err)
+	}
+}
+
+func TestScanBookRecordRow(t *testing.T) {
+	db, err := sql.Open("m2sfake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	fakeResults["row"] = &fakeResult{
+		columns: genBookRecordColumns,
+		values:  [][]driver.Value{{int64(3), "c", nil, int64(1), false, "2024-01-02T03:04:05Z", nil}},
+	}
+	obj, err := genScanBookRecordRow(db.QueryRow("row"))
+	if err != nil {
+		t.Fatalf("scan row failed. err=%v", err)
+	}
+	if obj.Id != 3 || obj.Title != "c" || obj.Price != nil || obj.CreatedAt.Year() != 2024 {
+		t.Errorf("obj = %+v", obj)
+	}
+
+	fakeResults["bad"] = &fakeResult{
+		columns: genBookRecordColumns,
+		values:  [][]driver.Value{{"x", "c", nil, int64(1), false, nil, nil}},
+	}
+	var keyErr *m2s.KeyError
+	if _, err = genScanBookRecordRow(db.QueryRow("bad")); !errors.As(err, &keyErr) || keyErr.Key != "id" {
+		t.Errorf("want key error. err=%v", err)
+	}
+}
@@ -0,0 +1,4 @@
+// Package typegen 不需要声明 MapToXxx 函数，直接根据类型生成转换函数
+package typegen
+
+//go:generate map2struct -type=ApiBookInfo,ApiItemInfo -pkg=github.com/adyzng/gotool/example/model -src=interface -strict
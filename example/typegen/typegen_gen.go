@@ -0,0 +1,142 @@
+// Auto generated code, DO NOT EDIT.
+
+package typegen
+
+import (
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+	"github.com/spf13/cast"
+)
+
+func genMapToApiBookInfo(src map[string]interface{}) (obj *model.ApiBookInfo, err error) {
+	obj = &model.ApiBookInfo{}
+	if err = genApplyApiBookInfo(src, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// genApplyApiBookInfo 只覆盖 src 中存在的字段
+func genApplyApiBookInfo(src map[string]interface{}, obj *model.ApiBookInfo) (err error) {
+	// 直接赋值的字段
+	if tmp, ok := src["book_id"]; ok {
+		if obj.Id, err = m2s.ToInt64E(tmp); err != nil {
+			return &m2s.KeyError{Key: "book_id", Err: err}
+		}
+	}
+	if tmp, ok := src["book_name"]; ok {
+		if obj.Name, err = cast.ToStringE(tmp); err != nil {
+			return &m2s.KeyError{Key: "book_name", Err: err}
+		}
+	}
+	if tmp, ok := src["copyright_info"]; ok {
+		if obj.CopyrightInfo, err = cast.ToStringE(tmp); err != nil {
+			return &m2s.KeyError{Key: "copyright_info", Err: err}
+		}
+	}
+	if tmp, ok := src["create_time"]; ok {
+		if obj.CreateTime, err = cast.ToStringE(tmp); err != nil {
+			return &m2s.KeyError{Key: "create_time", Err: err}
+		}
+	}
+	if tmp, ok := src["thumb_url"]; ok {
+		if obj.ThumbUrl, err = cast.ToStringE(tmp); err != nil {
+			return &m2s.KeyError{Key: "thumb_url", Err: err}
+		}
+	}
+	if tmp, ok := src["is_first_read"]; ok {
+		if obj.IsFirstRead, err = m2s.ToBoolE(tmp); err != nil {
+			return &m2s.KeyError{Key: "is_first_read", Err: err}
+		}
+	}
+
+	// 枚举类型
+	if tmp, ok := src["book_type"]; ok {
+		num, err := m2s.ToInt64E(tmp)
+		if err != nil {
+			return &m2s.KeyError{Key: "book_type", Err: err}
+		}
+		val := (model.BookType)(num)
+		obj.BookType = &val
+	}
+
+	// 带赋值表达式的（指针类型）
+	if tmp, ok := src["serial_count"]; ok {
+		val, err := m2s.ToInt32E(tmp)
+		if err != nil {
+			return &m2s.KeyError{Key: "serial_count", Err: err}
+		}
+		obj.SerialCount = &val
+	}
+	if tmp, ok := src["latest_read_time"]; ok {
+		val, err := m2s.ToInt64E(tmp)
+		if err != nil {
+			return &m2s.KeyError{Key: "latest_read_time", Err: err}
+		}
+		obj.LatestReadTime = &val
+	}
+	if tmp, ok := src["category"]; ok {
+		val, err := cast.ToStringE(tmp)
+		if err != nil {
+			return &m2s.KeyError{Key: "category", Err: err}
+		}
+		obj.Category = &val
+	}
+
+	return err
+}
+
+func genMapToApiItemInfo(src map[string]interface{}) (obj *model.ApiItemInfo, err error) {
+	if _, ok := src["item_id"]; !ok {
+		return nil, &m2s.KeyError{Key: "item_id", Err: m2s.ErrMissingKey}
+	}
+	if _, ok := src["title"]; !ok {
+		return nil, &m2s.KeyError{Key: "title", Err: m2s.ErrMissingKey}
+	}
+	obj = model.NewApiItemInfo()
+	if err = genApplyApiItemInfo(src, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// genApplyApiItemInfo 只覆盖 src 中存在的字段
+func genApplyApiItemInfo(src map[string]interface{}, obj *model.ApiItemInfo) (err error) {
+	// 直接赋值的字段
+	if tmp, ok := src["item_id"]; ok {
+		if obj.ItemId, err = m2s.ToInt64E(tmp); err != nil {
+			return &m2s.KeyError{Key: "item_id", Err: err}
+		}
+	}
+	if tmp, ok := src["title"]; ok {
+		if obj.Title, err = cast.ToStringE(tmp); err != nil {
+			return &m2s.KeyError{Key: "title", Err: err}
+		}
+	}
+	if tmp, ok := src["lang"]; ok {
+		if obj.Lang, err = cast.ToStringE(tmp); err != nil {
+			return &m2s.KeyError{Key: "lang", Err: err}
+		}
+	}
+
+	// 枚举类型
+	if tmp, ok := src["status"]; ok {
+		num, err := m2s.ToInt64E(tmp)
+		if err != nil {
+			return &m2s.KeyError{Key: "status", Err: err}
+		}
+		val := (model.ItemStatus)(num)
+		obj.Status = val
+	}
+
+	// 带赋值表达式的（指针类型）
+	if tmp, ok := src["score"]; ok {
+		val, err := m2s.ToFloat64E(tmp)
+		if err != nil {
+			return &m2s.KeyError{Key: "score", Err: err}
+		}
+		obj.Score = &val
+	}
+
+	return err
+}
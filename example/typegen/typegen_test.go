@@ -0,0 +1,80 @@
+package typegen
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/adyzng/gotool/example/model"
+	"github.com/adyzng/gotool/m2s"
+)
+
+func TestTypeGen(t *testing.T) {
+	book, err := genMapToApiBookInfo(map[string]interface{}{"book_id": float64(7), "book_name": "go"})
+	if err != nil {
+		t.Fatalf("convert book failed. err=%v", err)
+	}
+	if book.Id != 7 || book.Name != "go" {
+		t.Errorf("unexpected book. obj=%+v", book)
+	}
+
+	// 构造函数 model.NewApiItemInfo 设置的默认值
+	item, err := genMapToApiItemInfo(map[string]interface{}{"item_id": float64(1), "title": "go"})
+	if err != nil {
+		t.Fatalf("convert item failed. err=%v", err)
+	}
+	if item.ItemId != 1 || item.Title != "go" || item.Lang != "zh" {
+		t.Errorf("unexpected item. obj=%+v", item)
+	}
+
+	if _, err = genMapToApiItemInfo(map[string]interface{}{"item_id": "abc"}); err == nil {
+		t.Errorf("wrong type should fail")
+	}
+}
+
+func TestTypeGenInterfaceValues(t *testing.T) {
+	src := map[string]interface{}{
+		"book_id":          json.Number("9007199254740993"),
+		"serial_count":     float64(12),
+		"book_type":        "3",
+		"category":         "novel",
+		"is_first_read":    "true",
+		"latest_read_time": int64(1700000000),
+	}
+	book, err := genMapToApiBookInfo(src)
+	if err != nil {
+		t.Fatalf("convert book failed. err=%v", err)
+	}
+	if book.Id != 9007199254740993 || book.SerialCount == nil || *book.SerialCount != 12 || !book.IsFirstRead {
+		t.Errorf("unexpected book. obj=%+v", book)
+	}
+	if book.BookType == nil || *book.BookType != model.BookType_PAGE_RIGHT || book.Category == nil || *book.Category != "novel" {
+		t.Errorf("unexpected book. obj=%+v", book)
+	}
+
+	// 严格模式: 小数和溢出返回错误
+	var keyErr *m2s.KeyError
+	for key, val := range map[string]interface{}{"book_id": 1.5, "serial_count": float64(1 << 40)} {
+		if _, err = genMapToApiBookInfo(map[string]interface{}{key: val}); !errors.As(err, &keyErr) || keyErr.Key != key {
+			t.Errorf("key=%s val=%v want key error. err=%v", key, val, err)
+		}
+	}
+}
+
+func TestTypeGenRequired(t *testing.T) {
+	// thrift 结构体的 key 默认使用 thrift tag
+	_, err := genMapToApiItemInfo(map[string]interface{}{"item_id": float64(1)})
+	var keyErr *m2s.KeyError
+	if !errors.As(err, &keyErr) || keyErr.Key != "title" || !errors.Is(err, m2s.ErrMissingKey) {
+		t.Errorf("want missing title. err=%v", err)
+	}
+
+	// genApplyApiItemInfo 不检查 required，只覆盖存在的字段
+	item := model.NewApiItemInfo()
+	if err = genApplyApiItemInfo(map[string]interface{}{"status": float64(0)}, item); err != nil {
+		t.Fatalf("apply failed. err=%v", err)
+	}
+	if item.Status != model.ItemStatus_OFFLINE || item.Lang != "zh" {
+		t.Errorf("unexpected item. obj=%+v", item)
+	}
+}
@@ -5,4 +5,5 @@ go 1.16
 require (
 	github.com/spf13/cast v1.4.1
 	golang.org/x/tools v0.1.7
+	gopkg.in/yaml.v3 v3.0.1
 )
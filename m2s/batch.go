@@ -0,0 +1,70 @@
+package m2s
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// RowError 批量转换时第 Row 行（从 0 开始）转换失败
+type RowError struct {
+	Row int
+	Err error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("convert row failed. row=%d err=%v", e.Row, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// ForEach 对 [0, n) 执行 fn，最多使用 workers 个 goroutine，workers <= 1 时顺序执行；
+// 出错后不再分配新的行；行号是按顺序分配的，返回的 *RowError 是所有失败的行中行号最小的
+func ForEach(n, workers int, fn func(idx int) error) error {
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		for idx := 0; idx < n; idx++ {
+			if err := fn(idx); err != nil {
+				return &RowError{Row: idx, Err: err}
+			}
+		}
+		return nil
+	}
+
+	var (
+		next     int64 = -1
+		failed   int32
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr *RowError
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for atomic.LoadInt32(&failed) == 0 {
+				idx := int(atomic.AddInt64(&next, 1))
+				if idx >= n {
+					return
+				}
+				if err := fn(idx); err != nil {
+					atomic.StoreInt32(&failed, 1)
+					mu.Lock()
+					if firstErr == nil || idx < firstErr.Row {
+						firstErr = &RowError{Row: idx, Err: err}
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package m2s
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestForEach(t *testing.T) {
+	errBad := errors.New("bad row")
+	for _, workers := range []int{0, 1, 4, 100} {
+		var count int64
+		out := make([]int, 50)
+		err := ForEach(len(out), workers, func(idx int) error {
+			atomic.AddInt64(&count, 1)
+			out[idx] = idx * 2
+			return nil
+		})
+		if err != nil || count != 50 || out[49] != 98 {
+			t.Errorf("workers=%d count=%d err=%v", workers, count, err)
+		}
+
+		err = ForEach(len(out), workers, func(idx int) error {
+			if idx == 7 || idx == 30 {
+				return errBad
+			}
+			return nil
+		})
+		var rowErr *RowError
+		if !errors.As(err, &rowErr) || !errors.Is(err, errBad) || rowErr.Row != 7 {
+			t.Errorf("workers=%d err=%v", workers, err)
+		}
+	}
+
+	if err := ForEach(0, 4, func(idx int) error { return errors.New("never") }); err != nil {
+		t.Errorf("empty err=%v", err)
+	}
+}
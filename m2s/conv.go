@@ -0,0 +1,291 @@
+package m2s
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cast"
+)
+
+// float64、float32 能精确表示的最大整数 2^53、2^24, 超过后得到的整数可能已经失真（比如 json.Unmarshal 的结果）
+const (
+	maxExactFloat   = 1 << 53
+	maxExactFloat32 = 1 << 24
+)
+
+// ToInt64E 整数转换，支持 json.Number，float64 必须是整数且无精度损失
+func ToInt64E(i interface{}) (int64, error) { return toIntE(i, 64) }
+func ToInt32E(i interface{}) (int32, error) { v, err := toIntE(i, 32); return int32(v), err }
+func ToInt16E(i interface{}) (int16, error) { v, err := toIntE(i, 16); return int16(v), err }
+func ToInt8E(i interface{}) (int8, error)   { v, err := toIntE(i, 8); return int8(v), err }
+func ToIntE(i interface{}) (int, error)     { v, err := toIntE(i, strconv.IntSize); return int(v), err }
+
+func ToUint64E(i interface{}) (uint64, error) { return toUintE(i, 64) }
+func ToUint32E(i interface{}) (uint32, error) { v, err := toUintE(i, 32); return uint32(v), err }
+func ToUint16E(i interface{}) (uint16, error) { v, err := toUintE(i, 16); return uint16(v), err }
+func ToUint8E(i interface{}) (uint8, error)   { v, err := toUintE(i, 8); return uint8(v), err }
+func ToUintE(i interface{}) (uint, error)     { v, err := toUintE(i, strconv.IntSize); return uint(v), err }
+
+// ToFloat64E 浮点转换，在 cast 的基础上支持 json.Number
+func ToFloat64E(i interface{}) (float64, error) {
+	if n, ok := i.(json.Number); ok {
+		return strconv.ParseFloat(string(n), 64)
+	}
+	return cast.ToFloat64E(i)
+}
+
+func ToFloat32E(i interface{}) (float32, error) {
+	if n, ok := i.(json.Number); ok {
+		v, err := strconv.ParseFloat(string(n), 32)
+		return float32(v), err
+	}
+	return cast.ToFloat32E(i)
+}
+
+// ToBoolE 布尔转换，在 cast 的基础上支持所有数值类型和 json.Number，非 0 为 true（比如 MySQL 的 tinyint）
+func ToBoolE(i interface{}) (bool, error) {
+	switch i.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, json.Number:
+		f, err := ToFloat64E(i)
+		return f != 0, err
+	}
+	return cast.ToBoolE(i)
+}
+
+// 非严格模式：忽略错误，尽量返回转换后的值（与 cast 行为一致）
+// 超出目标类型范围时按位截断，比如 ToInt8("128") == -128，需要检查范围时使用 ToXxxE
+func ToInt64(i interface{}) int64     { v, _ := ToInt64E(i); return v }
+func ToInt32(i interface{}) int32     { v, _ := ToInt32E(i); return v }
+func ToInt16(i interface{}) int16     { v, _ := ToInt16E(i); return v }
+func ToInt8(i interface{}) int8       { v, _ := ToInt8E(i); return v }
+func ToInt(i interface{}) int         { v, _ := ToIntE(i); return v }
+func ToUint64(i interface{}) uint64   { v, _ := ToUint64E(i); return v }
+func ToUint32(i interface{}) uint32   { v, _ := ToUint32E(i); return v }
+func ToUint16(i interface{}) uint16   { v, _ := ToUint16E(i); return v }
+func ToUint8(i interface{}) uint8     { v, _ := ToUint8E(i); return v }
+func ToUint(i interface{}) uint       { v, _ := ToUintE(i); return v }
+func ToFloat64(i interface{}) float64 { v, _ := ToFloat64E(i); return v }
+func ToFloat32(i interface{}) float32 { v, _ := ToFloat32E(i); return v }
+func ToBool(i interface{}) bool       { v, _ := ToBoolE(i); return v }
+
+func toIntE(i interface{}, bitSize int) (int64, error) {
+	var v int64
+	switch s := i.(type) {
+	case nil:
+		return 0, nil
+	case int:
+		v = int64(s)
+	case int8:
+		v = int64(s)
+	case int16:
+		v = int64(s)
+	case int32:
+		v = int64(s)
+	case int64:
+		v = s
+	case uint, uint8, uint16, uint32, uint64:
+		u, err := toUintE(s, 64)
+		if err != nil {
+			return 0, err
+		}
+		if u > math.MaxInt64 {
+			return int64(u), fmt.Errorf("integer overflow. value=%v bits=%d", i, bitSize)
+		}
+		v = int64(u)
+	case float32:
+		return floatToInt(float64(s), maxExactFloat32, bitSize)
+	case float64:
+		return floatToInt(s, maxExactFloat, bitSize)
+	case json.Number:
+		return parseInt(string(s), bitSize)
+	case string:
+		return parseInt(s, bitSize)
+	case bool:
+		if s {
+			return 1, nil
+		}
+		return 0, nil
+	default:
+		v, err := cast.ToInt64E(i)
+		if err != nil {
+			return 0, err
+		}
+		return v, checkInt(v, bitSize)
+	}
+	return v, checkInt(v, bitSize)
+}
+
+func toUintE(i interface{}, bitSize int) (uint64, error) {
+	var v uint64
+	switch s := i.(type) {
+	case nil:
+		return 0, nil
+	case uint:
+		v = uint64(s)
+	case uint8:
+		v = uint64(s)
+	case uint16:
+		v = uint64(s)
+	case uint32:
+		v = uint64(s)
+	case uint64:
+		v = s
+	case int, int8, int16, int32, int64:
+		n, err := toIntE(s, 64)
+		if err != nil {
+			return 0, err
+		}
+		if n < 0 {
+			return uint64(n), fmt.Errorf("negative value to unsigned integer. value=%v", i)
+		}
+		v = uint64(n)
+	case float32:
+		return floatToUint(float64(s), maxExactFloat32, bitSize)
+	case float64:
+		return floatToUint(s, maxExactFloat, bitSize)
+	case json.Number:
+		return parseUint(string(s), bitSize)
+	case string:
+		return parseUint(s, bitSize)
+	case bool:
+		if s {
+			return 1, nil
+		}
+		return 0, nil
+	default:
+		v, err := cast.ToUint64E(i)
+		if err != nil {
+			return 0, err
+		}
+		return v, checkUint(v, bitSize)
+	}
+	return v, checkUint(v, bitSize)
+}
+
+func parseInt(s string, bitSize int) (int64, error) {
+	v, err := strconv.ParseInt(s, 10, bitSize)
+	if err == nil {
+		return v, nil
+	}
+	// "1e3"、"10.0" 这类合法的 JSON 数字
+	if f, ferr := strconv.ParseFloat(s, 64); ferr == nil {
+		return floatToInt(f, maxExactFloat, bitSize)
+	}
+	return v, fmt.Errorf("unable to cast %q to int%d. err=%v", s, bitSize, err)
+}
+
+func parseUint(s string, bitSize int) (uint64, error) {
+	v, err := strconv.ParseUint(s, 10, bitSize)
+	if err == nil {
+		return v, nil
+	}
+	if f, ferr := strconv.ParseFloat(s, 64); ferr == nil {
+		return floatToUint(f, maxExactFloat, bitSize)
+	}
+	return v, fmt.Errorf("unable to cast %q to uint%d. err=%v", s, bitSize, err)
+}
+
+// floatToInt exact 为浮点来源类型能精确表示的最大整数
+func floatToInt(f, exact float64, bitSize int) (int64, error) {
+	v := int64(f)
+	if f != math.Trunc(f) {
+		return v, fmt.Errorf("non-integral value to integer. value=%v", f)
+	}
+	if math.Abs(f) > exact {
+		return v, fmt.Errorf("lossy float to integer conversion. value=%v", f)
+	}
+	return v, checkInt(v, bitSize)
+}
+
+func floatToUint(f, exact float64, bitSize int) (uint64, error) {
+	if f < 0 {
+		return uint64(int64(f)), fmt.Errorf("negative value to unsigned integer. value=%v", f)
+	}
+	v := uint64(f)
+	if f != math.Trunc(f) {
+		return v, fmt.Errorf("non-integral value to unsigned integer. value=%v", f)
+	}
+	if f > exact {
+		return v, fmt.Errorf("lossy float to integer conversion. value=%v", f)
+	}
+	return v, checkUint(v, bitSize)
+}
+
+func checkInt(v int64, bitSize int) error {
+	if bitSize >= 64 {
+		return nil
+	}
+	if lim := int64(1) << (bitSize - 1); v < -lim || v >= lim {
+		return fmt.Errorf("integer overflow. value=%d bits=%d", v, bitSize)
+	}
+	return nil
+}
+
+func checkUint(v uint64, bitSize int) error {
+	if bitSize >= 64 {
+		return nil
+	}
+	if v >= uint64(1)<<bitSize {
+		return fmt.Errorf("integer overflow. value=%d bits=%d", v, bitSize)
+	}
+	return nil
+}
+
+// ToEnumE proto 枚举转换：字符串先按枚举名在 values（protoc-gen-go 生成的 Xxx_value）中查找，否则按数字转换
+func ToEnumE(i interface{}, values map[string]int32) (int32, error) {
+	if s, ok := i.(string); ok {
+		if v, ok := values[s]; ok {
+			return v, nil
+		}
+	}
+	return ToInt32E(i)
+}
+
+func ToEnum(i interface{}, values map[string]int32) int32 {
+	v, _ := ToEnumE(i, values)
+	return v
+}
+
+// UnmarshalJSON 将 JSON 字符串解析到 obj（必须是指针）；
+// 值不是 string/[]byte 时（比如 json.Unmarshal 已经解析过的 map、slice）先重新编码
+func UnmarshalJSON(i interface{}, obj interface{}) error {
+	var data []byte
+	switch v := i.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	case json.RawMessage:
+		data = v
+	default:
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return err
+		}
+	}
+	return json.Unmarshal(data, obj)
+}
+
+// SplitE 按 sep 拆分字符串并去掉每个元素两端的空白，空字符串返回空切片
+func SplitE(i interface{}, sep string) ([]string, error) {
+	s, err := cast.ToStringE(i)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(s) == "" {
+		return []string{}, nil
+	}
+	items := strings.Split(s, sep)
+	for idx, item := range items {
+		items[idx] = strings.TrimSpace(item)
+	}
+	return items, nil
+}
+
+func Split(i interface{}, sep string) []string {
+	items, _ := SplitE(i, sep)
+	return items
+}
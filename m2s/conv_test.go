@@ -0,0 +1,169 @@
+package m2s
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestToInt64E(t *testing.T) {
+	cases := []struct {
+		val     interface{}
+		want    int64
+		wantErr bool
+	}{
+		{val: nil, want: 0},
+		{val: "42", want: 42},
+		{val: "010", want: 10},
+		{val: "0x10", want: 0, wantErr: true},
+		{val: "0b1", want: 0, wantErr: true},
+		{val: json.Number("9007199254740993"), want: 9007199254740993},
+		{val: json.Number("1e3"), want: 1000},
+		{val: json.Number("1.5"), want: 1, wantErr: true},
+		{val: float64(123), want: 123},
+		{val: float64(1.5), want: 1, wantErr: true},
+		{val: float64(1 << 60), want: 1 << 60, wantErr: true},
+		{val: uint64(math.MaxUint64), want: -1, wantErr: true},
+		{val: "abc", want: 0, wantErr: true},
+	}
+	for _, c := range cases {
+		got, err := ToInt64E(c.val)
+		if got != c.want || (err != nil) != c.wantErr {
+			t.Errorf("ToInt64E(%#v) = %d, %v; want %d, err=%v", c.val, got, err, c.want, c.wantErr)
+		}
+	}
+}
+
+func TestToSmallIntE(t *testing.T) {
+	if _, err := ToInt8E("128"); err == nil {
+		t.Errorf("ToInt8E(128) should overflow")
+	}
+	if v, err := ToInt8E(json.Number("-128")); err != nil || v != -128 {
+		t.Errorf("ToInt8E(-128) = %d, %v", v, err)
+	}
+	if _, err := ToUint32E(float64(-1)); err == nil {
+		t.Errorf("ToUint32E(-1) should fail")
+	}
+	if v, err := ToUint16E(json.Number("65535")); err != nil || v != math.MaxUint16 {
+		t.Errorf("ToUint16E(65535) = %d, %v", v, err)
+	}
+	// 十进制解析，前面补 0 的数字不是八进制
+	if v, err := ToUint32E("0755"); err != nil || v != 755 {
+		t.Errorf("ToUint32E(0755) = %d, %v", v, err)
+	}
+	if _, err := ToUint32E("0o755"); err == nil {
+		t.Errorf("ToUint32E(0o755) should fail")
+	}
+	// 非严格模式按位截断
+	if v := ToInt8("128"); v != -128 {
+		t.Errorf("ToInt8(128) = %d, want -128", v)
+	}
+}
+
+func TestFloat32ToIntE(t *testing.T) {
+	if v, err := ToInt64E(float32(1 << 24)); err != nil || v != 1<<24 {
+		t.Errorf("ToInt64E(float32(2^24)) = %d, %v", v, err)
+	}
+	// float32 超过 2^24 的整数可能已经失真
+	if _, err := ToInt64E(float32(1 << 25)); err == nil {
+		t.Errorf("ToInt64E(float32(2^25)) should fail")
+	}
+	if _, err := ToUint32E(float32(1 << 25)); err == nil {
+		t.Errorf("ToUint32E(float32(2^25)) should fail")
+	}
+	if v, err := ToInt64E(float64(1 << 25)); err != nil || v != 1<<25 {
+		t.Errorf("ToInt64E(float64(2^25)) = %d, %v", v, err)
+	}
+}
+
+func TestJsonNumber(t *testing.T) {
+	dec := json.NewDecoder(strings.NewReader(`{"id": 1234567890123456789, "score": 9.5}`))
+	dec.UseNumber()
+
+	src := map[string]interface{}{}
+	if err := dec.Decode(&src); err != nil {
+		t.Fatalf("decode failed. err=%v", err)
+	}
+	if v, err := ToInt64E(src["id"]); err != nil || v != 1234567890123456789 {
+		t.Errorf("id = %d, %v", v, err)
+	}
+	if v, err := ToFloat64E(src["score"]); err != nil || v != 9.5 {
+		t.Errorf("score = %v, %v", v, err)
+	}
+}
+
+func TestToEnumE(t *testing.T) {
+	values := map[string]int32{"UNKNOWN": 0, "ONLINE": 1}
+	if v, err := ToEnumE("ONLINE", values); err != nil || v != 1 {
+		t.Errorf("ToEnumE(ONLINE) = %d, %v", v, err)
+	}
+	if v, err := ToEnumE(float64(1), values); err != nil || v != 1 {
+		t.Errorf("ToEnumE(1) = %d, %v", v, err)
+	}
+	if _, err := ToEnumE("OFFLINE", values); err == nil {
+		t.Errorf("ToEnumE(OFFLINE) should fail")
+	}
+}
+
+func TestToBoolE(t *testing.T) {
+	cases := []struct {
+		in      interface{}
+		want    bool
+		wantErr bool
+	}{
+		{true, true, false},
+		{"false", false, false},
+		{int64(1), true, false},
+		{uint8(0), false, false},
+		{float64(1), true, false},
+		{json.Number("0"), false, false},
+		{"maybe", false, true},
+		{json.Number("x"), false, true},
+	}
+	for _, c := range cases {
+		got, err := ToBoolE(c.in)
+		if (err != nil) != c.wantErr || (!c.wantErr && got != c.want) {
+			t.Errorf("ToBoolE(%#v) = %v, %v", c.in, got, err)
+		}
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	var tags []string
+	if err := UnmarshalJSON(`["a","b"]`, &tags); err != nil || len(tags) != 2 || tags[1] != "b" {
+		t.Errorf("UnmarshalJSON(string) = %v, %v", tags, err)
+	}
+
+	var ext map[string]int
+	if err := UnmarshalJSON(map[string]interface{}{"n": float64(1)}, &ext); err != nil || ext["n"] != 1 {
+		t.Errorf("UnmarshalJSON(map) = %v, %v", ext, err)
+	}
+
+	if err := UnmarshalJSON("{", &ext); err == nil {
+		t.Errorf("UnmarshalJSON(invalid) should fail")
+	}
+}
+
+func TestSplitE(t *testing.T) {
+	cases := []struct {
+		in   interface{}
+		sep  string
+		want []string
+	}{
+		{"1, 2 ,3", ",", []string{"1", "2", "3"}},
+		{"a|b||c", "|", []string{"a", "b", "", "c"}},
+		{" ", ",", []string{}},
+		{json.Number("42"), ",", []string{"42"}},
+	}
+	for _, c := range cases {
+		got, err := SplitE(c.in, c.sep)
+		if err != nil || !reflect.DeepEqual(got, c.want) {
+			t.Errorf("SplitE(%#v, %q) = %q, %v", c.in, c.sep, got, err)
+		}
+	}
+	if _, err := SplitE([]int{1}, ","); err == nil {
+		t.Errorf("SplitE([]int) should fail")
+	}
+}
@@ -0,0 +1,36 @@
+package m2s
+
+import (
+	"errors"
+	"fmt"
+)
+
+// CSVError 生成的 CSV reader 转换失败，Record 为记录的序号（表头为第 1 条），字段中包含换行时不是文件的行号；Column 从 1 开始，未知时为 0
+type CSVError struct {
+	Record int
+	Column int
+	Err    error
+}
+
+func (e *CSVError) Error() string {
+	return fmt.Sprintf("convert csv failed. record=%d column=%d err=%v", e.Record, e.Column, e.Err)
+}
+
+func (e *CSVError) Unwrap() error {
+	return e.Err
+}
+
+// NewCSVError 根据 *KeyError 的 key 在表头中的位置确定列号
+func NewCSVError(record int, header []string, err error) error {
+	csvErr := &CSVError{Record: record, Err: err}
+	var keyErr *KeyError
+	if errors.As(err, &keyErr) {
+		for idx, key := range header {
+			if key == keyErr.Key {
+				csvErr.Column = idx + 1
+				break
+			}
+		}
+	}
+	return csvErr
+}
@@ -0,0 +1,886 @@
+package m2s
+
+import (
+	"errors"
+	"fmt"
+	"net/textproto"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+// Decode 运行时基于反射的转换，key 的命名、tag 选项、嵌套结构体、切片、枚举、指针和错误规则与 map2struct 生成的 genMapToXxx 相同：
+// 先检查 thrift required 字段，再把 *obj 重置为零值并调用 Default()（如果有），最后调用 Apply。
+// 反射无法找到 NewXxx() 构造函数，这类模型请先调用 NewXxx() 再使用 Apply；
+// 生成代码也不支持的字段（比如没有 prefix 的嵌套结构体、map）在 src 中存在时返回 *KeyError（ErrUnsupportedField）。
+func Decode(src interface{}, obj interface{}, opts ...Option) error {
+	rv, err := structValue(obj)
+	if err != nil {
+		return err
+	}
+	sv, err := newSource(src)
+	if err != nil {
+		return err
+	}
+
+	o := newOptions(opts)
+	plan := getPlan(rv.Type(), sv, o)
+	for _, key := range plan.required {
+		if !sv.has(key) {
+			return &KeyError{Key: key, Err: ErrMissingKey}
+		}
+	}
+
+	rv.Set(reflect.Zero(rv.Type()))
+	if d, ok := obj.(interface{ Default() }); ok {
+		d.Default()
+	}
+	return applyPlan(sv, rv, plan, o)
+}
+
+// Apply 运行时基于反射的转换，同 genApplyXxx 只覆盖 src 中存在的字段，支持的字段同 Decode
+func Apply(src interface{}, obj interface{}, opts ...Option) error {
+	rv, err := structValue(obj)
+	if err != nil {
+		return err
+	}
+	sv, err := newSource(src)
+	if err != nil {
+		return err
+	}
+	o := newOptions(opts)
+	return applyPlan(sv, rv, getPlan(rv.Type(), sv, o), o)
+}
+
+type options struct {
+	tag       string
+	tagSet    bool
+	strict    bool
+	disallow  bool
+	onUnknown func(key string)
+	fallback  bool
+	flatten   bool
+	sep       string
+	maxIndex  int
+}
+
+type Option func(o *options)
+
+// Tag 作为 map key 的 struct tag，默认 json，同 map2struct -tag
+func Tag(tag string) Option {
+	return func(o *options) { o.tag, o.tagSet = tag, true }
+}
+
+// Strict 严格模式，同 map2struct -strict
+func Strict() Option {
+	return func(o *options) { o.strict = true }
+}
+
+// DisallowUnknown 存在未知 key 时返回 *UnknownKeyError，同 map2struct -unknown=error
+func DisallowUnknown() Option {
+	return func(o *options) { o.disallow = true }
+}
+
+// OnUnknown 未知 key 的回调，同 map2struct -unknown=callback
+func OnUnknown(fn func(key string)) Option {
+	return func(o *options) { o.onUnknown = fn }
+}
+
+// Flatten 展开所有嵌套结构体和切片字段，同 //map2struct:flatten [sep] 指令，sep 为空时使用 "."
+func Flatten(sep string) Option {
+	return func(o *options) { o.flatten, o.sep = true, sep }
+}
+
+// MaxIndex 带下标的 key 中下标的上限，同 map2struct -maxindex
+func MaxIndex(n int) Option {
+	return func(o *options) { o.maxIndex = n }
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{tag: "json", maxIndex: 1000}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.sep == "" {
+		o.sep = "."
+	}
+	return o
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+
+	enumValues sync.Map // key: reflect.Type, value: map[string]int32
+	planCache  sync.Map // key: planKey, value: *structPlan
+)
+
+// RegisterEnum 注册 proto 枚举的名字映射（protoc-gen-go 生成的 Xxx_value），反射无法找到包级变量
+//
+//	m2s.RegisterEnum(model.BookStatus(0), model.BookStatus_value)
+func RegisterEnum(enum interface{}, values map[string]int32) {
+	enumValues.Store(reflect.TypeOf(enum), values)
+}
+
+// source 源 map: map[string]xxx 或者多值 map（url.Values、http.Header、map[string][]xxx）
+type source struct {
+	sv     reflect.Value
+	multi  bool
+	header bool
+}
+
+func newSource(src interface{}) (*source, error) {
+	sv := reflect.ValueOf(src)
+	if sv.Kind() != reflect.Map || sv.Type().Key().Kind() != reflect.String {
+		return nil, fmt.Errorf("src must be map[string]xxx. type=%T", src)
+	}
+	typ := sv.Type()
+	return &source{
+		sv:     sv,
+		multi:  typ.Elem().Kind() == reflect.Slice,
+		header: typ.PkgPath() == "net/http" && typ.Name() == "Header",
+	}, nil
+}
+
+// raw key 对应的值，多值 map 为整个切片
+func (s *source) raw(key string) (reflect.Value, bool) {
+	mv := s.sv.MapIndex(reflect.ValueOf(key).Convert(s.sv.Type().Key()))
+	return mv, mv.IsValid()
+}
+
+func (s *source) has(key string) bool {
+	_, ok := s.raw(key)
+	return ok
+}
+
+// first key 对应的值，多值 map 取第一个值，值为空切片时视为不存在
+func (s *source) first(key string) (interface{}, bool) {
+	mv, ok := s.raw(key)
+	if !ok {
+		return nil, false
+	}
+	if s.multi {
+		if mv.Len() == 0 {
+			return nil, false
+		}
+		return mv.Index(0).Interface(), true
+	}
+	return mv.Interface(), true
+}
+
+func (s *source) keys() []string {
+	keys := make([]string, 0, s.sv.Len())
+	for _, k := range s.sv.MapKeys() {
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// fieldKind 字段的生成方式，顺序与生成代码中赋值的顺序相同
+type fieldKind int
+
+const (
+	kindDirect fieldKind = iota
+	kindEnum
+	kindAssign
+	kindOneof
+	kindSlice
+	kindIndexed
+	kindJSON
+	kindOther
+)
+
+type planKey struct {
+	typ     reflect.Type
+	multi   bool
+	header  bool
+	tag     string
+	tagSet  bool
+	flatten bool
+	sep     string
+}
+
+type structPlan struct {
+	fields   []*fieldPlan // 按 kind 排序
+	indexed  []*fieldPlan
+	known    map[string]bool // 不带下标的 key
+	required []string
+}
+
+type fieldPlan struct {
+	kind    fieldKind
+	key     string       // 相对于 plan 的 key，带下标的切片为下标之前的前缀
+	path    []int        // 字段的下标，展开的嵌套结构体为多级
+	typ     reflect.Type // 值的类型，指针、切片时为元素类型
+	pointer bool
+	wrapper reflect.Type // proto oneof 的包装类型（指针）
+	sep     string       // 切片: 分隔符；带下标的切片: 下标之后的分隔符
+	elem    *structPlan  // 带下标的切片: 结构体元素
+}
+
+// knownKey 去掉元素前缀后的 key 是否为结构体的字段，同生成的 genApplyXxxElemKey
+func (p *structPlan) knownKey(key string) bool {
+	if p.known[key] {
+		return true
+	}
+	for _, fp := range p.indexed {
+		if _, ok := KeyIndex(key, fp.key, fp.sep, fp.elemKnown()); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (fp *fieldPlan) elemKnown() func(key string) bool {
+	if fp.elem == nil {
+		return nil
+	}
+	return fp.elem.knownKey
+}
+
+func structValue(obj interface{}) (reflect.Value, error) {
+	rv := reflect.ValueOf(obj)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("obj must be a non-nil pointer to struct. type=%T", obj)
+	}
+	return rv.Elem(), nil
+}
+
+func applyPlan(sv *source, rv reflect.Value, plan *structPlan, o *options) error {
+	if o.disallow || o.onUnknown != nil {
+		var unknown []string
+		for _, key := range sv.keys() {
+			if !isKnown(plan, key, o) {
+				if o.onUnknown != nil {
+					o.onUnknown(key)
+				}
+				unknown = append(unknown, key)
+			}
+		}
+		if o.disallow && len(unknown) > 0 {
+			return &UnknownKeyError{Keys: unknown}
+		}
+	}
+	return apply(sv, rv, plan, "", o)
+}
+
+// isKnown 顶层的 key 是否已知，非严格模式下超过 maxIndex 的下标视为未知
+func isKnown(plan *structPlan, key string, o *options) bool {
+	if plan.known[key] {
+		return true
+	}
+	for _, fp := range plan.indexed {
+		if idx, ok := KeyIndex(key, fp.key, fp.sep, fp.elemKnown()); ok && (o.strict || idx < o.maxIndex) {
+			return true
+		}
+	}
+	return false
+}
+
+// apply 按 plan 赋值，prefix 为切片元素的 key 前缀
+func apply(sv *source, rv reflect.Value, plan *structPlan, prefix string, o *options) error {
+	indexed := false
+	for _, fp := range plan.fields {
+		if fp.kind > kindIndexed && !indexed {
+			indexed = true
+			if err := applyIndexed(sv, rv, plan, prefix, o); err != nil {
+				return err
+			}
+		}
+		if err := applyField(sv, rv, fp, prefix, o); err != nil {
+			return err
+		}
+	}
+	if !indexed {
+		return applyIndexed(sv, rv, plan, prefix, o)
+	}
+	return nil
+}
+
+func applyField(sv *source, rv reflect.Value, fp *fieldPlan, prefix string, o *options) error {
+	key := prefix + fp.key
+	switch fp.kind {
+	case kindSlice:
+		var items []interface{}
+		if fp.sep != "" {
+			tmp, ok := sv.first(key)
+			if !ok {
+				return nil
+			}
+			list, err := SplitE(tmp, fp.sep)
+			if err != nil && o.strict {
+				return &KeyError{Key: key, Err: err}
+			}
+			for _, item := range list {
+				items = append(items, item)
+			}
+		} else {
+			vals, ok := sv.raw(key)
+			if !ok {
+				return nil
+			}
+			for i := 0; i < vals.Len(); i++ {
+				items = append(items, vals.Index(i).Interface())
+			}
+		}
+		field := fieldByPath(rv, fp.path)
+		val := reflect.MakeSlice(field.Type(), 0, len(items))
+		for idx, item := range items {
+			v, err := convertKind(item, fp.typ)
+			if err != nil && o.strict {
+				return &KeyError{Key: key, Err: &ElemError{Index: idx, Err: err}}
+			}
+			val = reflect.Append(val, v)
+		}
+		field.Set(val)
+
+	case kindJSON:
+		tmp, ok := sv.first(key)
+		if !ok {
+			return nil
+		}
+		field := fieldByPath(rv, fp.path)
+		if err := UnmarshalJSON(tmp, field.Addr().Interface()); err != nil && o.strict {
+			return &KeyError{Key: key, Err: err}
+		}
+
+	case kindOther:
+		if sv.has(key) {
+			return &KeyError{Key: key, Err: ErrUnsupportedField}
+		}
+
+	default:
+		tmp, ok := sv.first(key)
+		if !ok {
+			return nil
+		}
+		field := fieldByPath(rv, fp.path)
+		val, err := convertValue(tmp, fp.typ)
+		if err != nil && o.strict {
+			return &KeyError{Key: key, Err: err}
+		}
+		if fp.pointer {
+			ptr := reflect.New(fp.typ)
+			ptr.Elem().Set(val)
+			val = ptr
+		}
+		if fp.wrapper != nil {
+			wv := reflect.New(fp.wrapper.Elem())
+			wv.Elem().Field(0).Set(val)
+			val = wv
+		}
+		field.Set(val)
+	}
+	return nil
+}
+
+// applyIndexed 带下标的 key 组成的切片: 先计算所有切片的长度，保留已有的元素，只在下标超出时追加
+func applyIndexed(sv *source, rv reflect.Value, plan *structPlan, prefix string, o *options) error {
+	if len(plan.indexed) == 0 {
+		return nil
+	}
+	indexLen := make([]int, len(plan.indexed))
+	for _, key := range sv.keys() {
+		for i, fp := range plan.indexed {
+			idx, ok := KeyIndex(key, prefix+fp.key, fp.sep, fp.elemKnown())
+			if ok && idx >= o.maxIndex {
+				if o.strict {
+					return &KeyError{Key: key, Err: &ElemError{Index: idx, Err: ErrIndexRange}}
+				}
+				continue
+			}
+			if ok && idx >= indexLen[i] {
+				indexLen[i] = idx + 1
+			}
+		}
+	}
+
+	for i, fp := range plan.indexed {
+		n := indexLen[i]
+		if n == 0 {
+			continue
+		}
+		field := fieldByPath(rv, fp.path)
+		if grow := n - field.Len(); grow > 0 {
+			field.Set(reflect.AppendSlice(field, reflect.MakeSlice(field.Type(), grow, grow)))
+			if fp.elem != nil && !fp.pointer {
+				for j := n - grow; j < n; j++ {
+					callDefault(field.Index(j).Addr())
+				}
+			}
+		}
+		for j := 0; j < n; j++ {
+			elem := field.Index(j)
+			if fp.elem == nil {
+				key := prefix + fp.key + strconv.Itoa(j)
+				tmp, ok := sv.first(key)
+				if !ok {
+					continue
+				}
+				val, err := convertKind(tmp, fp.typ)
+				if err != nil && o.strict {
+					return &KeyError{Key: key, Err: err}
+				}
+				elem.Set(val)
+				continue
+			}
+			if fp.pointer {
+				if elem.IsNil() {
+					elem.Set(newStruct(fp.typ))
+				}
+				elem = elem.Elem()
+			}
+			if err := apply(sv, elem, fp.elem, prefix+fp.key+strconv.Itoa(j)+fp.sep, o); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// fieldByPath 按下标取字段，展开的嵌套结构体为 nil 指针时创建
+func fieldByPath(rv reflect.Value, path []int) reflect.Value {
+	for i, idx := range path {
+		rv = rv.Field(idx)
+		if i == len(path)-1 {
+			break
+		}
+		if rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				rv.Set(newStruct(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+	}
+	return rv
+}
+
+// newStruct 创建结构体并调用 Default()（如果有）
+func newStruct(typ reflect.Type) reflect.Value {
+	ptr := reflect.New(typ)
+	callDefault(ptr)
+	return ptr
+}
+
+func callDefault(ptr reflect.Value) {
+	if d, ok := ptr.Interface().(interface{ Default() }); ok {
+		d.Default()
+	}
+}
+
+func getPlan(typ reflect.Type, sv *source, o *options) *structPlan {
+	pk := planKey{typ: typ, multi: sv.multi, header: sv.header, tag: o.tag, tagSet: o.tagSet, flatten: o.flatten, sep: o.sep}
+	if plan, ok := planCache.Load(pk); ok {
+		return plan.(*structPlan)
+	}
+
+	b := &planBuilder{pk: pk}
+	plan := &structPlan{known: map[string]bool{}}
+	b.build(plan, typ, &planScope{types: []reflect.Type{typ}})
+	planCache.Store(pk, plan)
+	return plan
+}
+
+// planBuilder 与生成器的 structFields 相同的规则分析结构体的字段
+type planBuilder struct {
+	pk planKey
+}
+
+type planScope struct {
+	prefix  string
+	path    []int
+	dynamic bool           // 切片元素中的字段，不检查 required
+	types   []reflect.Type // 正在展开的结构体，避免递归
+}
+
+func (b *planBuilder) build(plan *structPlan, typ reflect.Type, scope *planScope) {
+	isProto, isThrift := isProtoStruct(typ), isThriftStruct(typ)
+	add := func(fp *fieldPlan) {
+		if fp.kind == kindIndexed {
+			plan.indexed = append(plan.indexed, fp)
+		} else {
+			plan.known[fp.key] = true
+			plan.fields = append(plan.fields, fp)
+		}
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		if sf.PkgPath != "" || strings.HasPrefix(sf.Name, "XXX_") {
+			continue
+		}
+		path := append(scope.path[:len(scope.path):len(scope.path)], i)
+
+		// proto oneof: 每个包装类型对应一个 key
+		if _, ok := sf.Tag.Lookup("protobuf_oneof"); ok && isProto {
+			for _, wt := range oneofWrappers(typ) {
+				if !wt.Implements(sf.Type) {
+					continue
+				}
+				wf := wt.Elem().Field(0)
+				fp := b.classify(wf.Type, wf.Tag)
+				fp.key, fp.path = scope.prefix+protoName(wf.Tag), path
+				if fp.kind != kindOther {
+					fp.kind, fp.wrapper = kindOneof, wt
+				}
+				add(fp)
+			}
+			continue
+		}
+
+		key := b.keyName(sf, isProto, isThrift)
+		if key == "" {
+			continue
+		}
+
+		// 嵌套结构体和带下标的切片: author.name, items.0.title
+		if prefix, ok := b.flattenPrefix(sf, key); ok && b.flatten(plan, sf, scope, path, prefix, add) {
+			continue
+		}
+
+		// thrift 的 required 字段不受 Strict 影响
+		if isThrift && tagHasOption(sf.Tag.Get("thrift"), "required") && !scope.dynamic {
+			plan.required = append(plan.required, scope.prefix+key)
+		}
+		fp := b.classify(sf.Type, sf.Tag)
+		fp.key, fp.path = scope.prefix+key, path
+		add(fp)
+	}
+
+	sort.SliceStable(plan.fields, func(i, j int) bool {
+		return plan.fields[i].kind < plan.fields[j].kind
+	})
+}
+
+// keyName 字段对应的 key，规则同生成器: tag（多值 map 为 form/query/header）> json tag > 字段名，
+// thrift 结构体默认取 thrift tag 的名字，m2s tag 的名字优先，proto 结构体取 protobuf tag 的 json= 部分，http.Header 规范化
+func (b *planBuilder) keyName(sf reflect.StructField, isProto, isThrift bool) string {
+	key := keyName(sf, keyTag(sf, b.sourceTags()))
+	if isThrift && !b.pk.multi && !b.pk.tagSet {
+		if name := tagName(sf.Tag, "thrift"); name != "" {
+			key = name
+		}
+	}
+	if name := tagName(sf.Tag, "m2s"); name != "" {
+		key = name
+		if name == "-" {
+			key = ""
+		}
+	}
+	if isProto && (b.pk.tag == "json" || b.pk.tag == "protobuf") {
+		if name := protoName(sf.Tag); name != "" {
+			key = name
+		}
+	}
+	if b.pk.header {
+		key = textproto.CanonicalMIMEHeaderKey(key)
+	}
+	return key
+}
+
+// sourceTags 指定了 Tag 或者不是多值 map 时只使用该 tag，否则 http.Header 使用 header tag，其他多值 map 依次使用 form、query tag
+func (b *planBuilder) sourceTags() []string {
+	switch {
+	case b.pk.tagSet || !b.pk.multi:
+		return []string{b.pk.tag}
+	case b.pk.header:
+		return []string{"header"}
+	default:
+		return []string{"form", "query"}
+	}
+}
+
+// flattenPrefix 嵌套结构体或切片字段的 key 前缀: m2s:",prefix=author." 或者 Flatten 选项
+func (b *planBuilder) flattenPrefix(sf reflect.StructField, key string) (string, bool) {
+	if prefix, ok := tagOption(sf.Tag, "prefix"); ok {
+		return prefix, true
+	}
+	_, isJSON := tagOption(sf.Tag, "json")
+	sep, _ := tagOption(sf.Tag, "sep")
+	if !b.pk.flatten || isJSON || sep != "" || (b.pk.multi && sf.Type.Kind() == reflect.Slice && isBasic(sf.Type.Elem())) {
+		return "", false
+	}
+	return key + b.pk.sep, true
+}
+
+// flatten 展开嵌套结构体的字段，或者添加带下标的切片字段；不是结构体或切片时返回 false
+func (b *planBuilder) flatten(plan *structPlan, sf reflect.StructField, scope *planScope, path []int, prefix string, add func(*fieldPlan)) bool {
+	typ := sf.Type
+	if typ.Kind() == reflect.Slice {
+		elem, pointer := typ.Elem(), false
+		if elem.Kind() == reflect.Ptr {
+			elem, pointer = elem.Elem(), true
+		}
+		fp := &fieldPlan{kind: kindIndexed, key: scope.prefix + prefix, path: path, typ: elem, pointer: pointer, sep: b.pk.sep}
+		if isBasic(elem) && !pointer {
+			add(fp)
+			return true
+		}
+		if !isNestedStruct(elem) || inTypes(scope.types, elem) {
+			return false
+		}
+		fp.elem = &structPlan{known: map[string]bool{}}
+		b.build(fp.elem, elem, &planScope{dynamic: true, types: append(scope.types[:len(scope.types):len(scope.types)], elem)})
+		add(fp)
+		return true
+	}
+
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if !isNestedStruct(typ) || inTypes(scope.types, typ) {
+		return false
+	}
+	b.build(plan, typ, &planScope{
+		prefix:  scope.prefix + prefix,
+		path:    path,
+		dynamic: scope.dynamic,
+		types:   append(scope.types[:len(scope.types):len(scope.types)], typ),
+	})
+	return true
+}
+
+// classify 根据字段类型确定赋值方式，同生成器的 classifyField；生成代码不支持的类型为 kindOther
+func (b *planBuilder) classify(typ reflect.Type, tag reflect.StructTag) *fieldPlan {
+	fp := &fieldPlan{kind: kindOther, typ: typ}
+	if _, ok := tagOption(tag, "json"); ok {
+		fp.kind = kindJSON
+		return fp
+	}
+
+	if typ.Kind() == reflect.Slice {
+		elem := typ.Elem()
+		sep, _ := tagOption(tag, "sep")
+		switch {
+		case elem.Kind() == reflect.Ptr || !isBasicKind(elem.Kind()):
+		case sep != "": // 分隔符拼接的列表: 1,2,3
+			fp.kind, fp.typ, fp.sep = kindSlice, elem, sep
+		case b.pk.multi && !(elem.Kind() == reflect.Uint8 && elem.PkgPath() == ""): // 多值 map 的切片字段，[]byte 除外
+			fp.kind, fp.typ = kindSlice, elem
+		}
+		return fp
+	}
+
+	if typ.Kind() == reflect.Ptr {
+		fp.typ, fp.pointer = typ.Elem(), true
+	}
+	switch {
+	case fp.typ == durationType || fp.typ == timeType:
+		fp.kind = kindDirect
+	case fp.typ.PkgPath() == "time" || !isBasicKind(fp.typ.Kind()):
+		return fp
+	case fp.typ.PkgPath() != "": // 底层为基本类型的枚举
+		fp.kind = kindEnum
+		return fp
+	default:
+		fp.kind = kindDirect
+	}
+	if fp.pointer {
+		fp.kind = kindAssign
+	}
+	return fp
+}
+
+func convertValue(val interface{}, typ reflect.Type) (reflect.Value, error) {
+	var out interface{}
+	var err error
+	switch typ {
+	case durationType:
+		out, err = cast.ToDurationE(val)
+		return reflect.ValueOf(out), err
+	case timeType:
+		out, err = cast.ToTimeE(val)
+		return reflect.ValueOf(out), err
+	}
+	return convertKind(val, typ)
+}
+
+// convertKind 按底层类型转换，枚举（底层为 int32 且注册了名字）先按名字转换
+func convertKind(val interface{}, typ reflect.Type) (reflect.Value, error) {
+	var out interface{}
+	var err error
+	switch typ.Kind() {
+	case reflect.String:
+		out, err = cast.ToStringE(val)
+	case reflect.Bool:
+		out, err = ToBoolE(val)
+	case reflect.Float32:
+		out, err = ToFloat32E(val)
+	case reflect.Float64:
+		out, err = ToFloat64E(val)
+	case reflect.Int:
+		out, err = ToIntE(val)
+	case reflect.Int8:
+		out, err = ToInt8E(val)
+	case reflect.Int16:
+		out, err = ToInt16E(val)
+	case reflect.Int32:
+		if values, ok := enumValues.Load(typ); ok {
+			out, err = ToEnumE(val, values.(map[string]int32))
+		} else {
+			out, err = ToInt32E(val)
+		}
+	case reflect.Int64:
+		out, err = ToInt64E(val)
+	case reflect.Uint:
+		out, err = ToUintE(val)
+	case reflect.Uint8:
+		out, err = ToUint8E(val)
+	case reflect.Uint16:
+		out, err = ToUint16E(val)
+	case reflect.Uint32:
+		out, err = ToUint32E(val)
+	case reflect.Uint64:
+		out, err = ToUint64E(val)
+	default:
+		return reflect.Zero(typ), errors.New("unsupported type " + typ.String())
+	}
+	return reflect.ValueOf(out).Convert(typ), err
+}
+
+// keyName 与 parse.TypeInfo.KeyName 一致：tag > json tag > 字段名，"-" 忽略
+func keyName(sf reflect.StructField, tag string) string {
+	for _, name := range []string{tag, "json"} {
+		if name == "" {
+			continue
+		}
+		if val, ok := sf.Tag.Lookup(name); ok {
+			if val == "-" {
+				return ""
+			}
+			if key := strings.Split(val, ",")[0]; key != "" {
+				return key
+			}
+		}
+	}
+	return sf.Name
+}
+
+// keyTag 字段上第一个存在的 tag，都不存在时使用第一个
+func keyTag(sf reflect.StructField, tags []string) string {
+	for _, tag := range tags {
+		if _, ok := sf.Tag.Lookup(tag); ok {
+			return tag
+		}
+	}
+	return tags[0]
+}
+
+func tagName(tag reflect.StructTag, name string) string {
+	return strings.Split(tag.Get(name), ",")[0]
+}
+
+// tagOption m2s tag 中的选项，与 parse.TypeInfo.TagOption 一致，值为逗号时写作 sep=,
+func tagOption(tag reflect.StructTag, name string) (value string, ok bool) {
+	val := tag.Get("m2s")
+	if val == "" {
+		return "", false
+	}
+	opts := strings.Split(val, ",")[1:]
+	for idx, opt := range opts {
+		if opt == name {
+			return "", true
+		}
+		if strings.HasPrefix(opt, name+"=") {
+			value = strings.TrimPrefix(opt, name+"=")
+			if value == "" && idx+1 < len(opts) && opts[idx+1] == "" {
+				value = ","
+			}
+			return value, true
+		}
+	}
+	return "", false
+}
+
+func protoName(tag reflect.StructTag) string {
+	name := ""
+	for _, opt := range strings.Split(tag.Get("protobuf"), ",") {
+		switch {
+		case strings.HasPrefix(opt, "json="):
+			return strings.TrimPrefix(opt, "json=")
+		case strings.HasPrefix(opt, "name="):
+			name = strings.TrimPrefix(opt, "name=")
+		}
+	}
+	return name
+}
+
+func tagHasOption(val, option string) bool {
+	ss := strings.Split(val, ",")
+	for _, s := range ss[1:] {
+		if s == option {
+			return true
+		}
+	}
+	return false
+}
+
+func isBasicKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// isBasic 没有命名的基本类型，命名的基本类型为枚举
+func isBasic(typ reflect.Type) bool {
+	return typ.PkgPath() == "" && isBasicKind(typ.Kind())
+}
+
+// isNestedStruct 可以展开的命名结构体，time.Time 除外
+func isNestedStruct(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Struct && typ.Name() != "" && typ != timeType
+}
+
+func inTypes(types []reflect.Type, typ reflect.Type) bool {
+	for _, t := range types {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+func isProtoStruct(typ reflect.Type) bool {
+	for i := 0; i < typ.NumField(); i++ {
+		tag := typ.Field(i).Tag
+		if _, ok := tag.Lookup("protobuf"); ok {
+			return true
+		}
+		if _, ok := tag.Lookup("protobuf_oneof"); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func isThriftStruct(typ reflect.Type) bool {
+	for i := 0; i < typ.NumField(); i++ {
+		if _, ok := typ.Field(i).Tag.Lookup("thrift"); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// oneofWrappers proto oneof 的包装类型，反射只能通过 XXX_OneofWrappers() 获得
+func oneofWrappers(typ reflect.Type) []reflect.Type {
+	ow, ok := reflect.New(typ).Interface().(interface{ XXX_OneofWrappers() []interface{} })
+	if !ok {
+		return nil
+	}
+	var list []reflect.Type
+	for _, w := range ow.XXX_OneofWrappers() {
+		list = append(list, reflect.TypeOf(w))
+	}
+	return list
+}
@@ -0,0 +1,326 @@
+package m2s
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+type testStatus int32
+
+type testModel struct {
+	Id       int64       `json:"id" thrift:"id,1,required"`
+	Name     string      `json:"name"`
+	Score    *float64    `json:"score,omitempty"`
+	Status   testStatus  `json:"status"`
+	Level    *testStatus `json:"level"`
+	Ignored  string      `json:"-"`
+	Untagged bool
+	Nested   struct{} `json:"nested"`
+	internal int
+}
+
+func (m *testModel) Default() {
+	m.Name = "default"
+}
+
+func TestDecode(t *testing.T) {
+	src := map[string]interface{}{
+		"id":       json.Number("9007199254740993"),
+		"score":    float64(9.5),
+		"status":   "2",
+		"level":    float64(3),
+		"Untagged": true,
+		"Ignored":  "x",
+	}
+
+	obj := &testModel{Ignored: "keep"}
+	if err := Decode(src, obj); err != nil {
+		t.Fatalf("decode failed. err=%v", err)
+	}
+	if obj.Id != 9007199254740993 || obj.Name != "default" || obj.Status != 2 || !obj.Untagged {
+		t.Errorf("obj = %+v", obj)
+	}
+	if obj.Score == nil || *obj.Score != 9.5 || obj.Level == nil || *obj.Level != 3 {
+		t.Errorf("obj = %+v", obj)
+	}
+	if obj.Ignored != "" {
+		t.Errorf("Decode should reset obj. Ignored=%s", obj.Ignored)
+	}
+}
+
+func TestDecodeStrict(t *testing.T) {
+	obj := &testModel{}
+	err := Decode(map[string]string{"name": "x"}, obj, Strict())
+	var ke *KeyError
+	if !errors.As(err, &ke) || ke.Key != "id" || !errors.Is(err, ErrMissingKey) {
+		t.Errorf("err = %v, want missing key id", err)
+	}
+
+	err = Decode(map[string]string{"id": "1", "status": "x"}, obj, Strict())
+	if !errors.As(err, &ke) || ke.Key != "status" {
+		t.Errorf("err = %v, want key error status", err)
+	}
+
+	// 非严格模式忽略错误，required 字段仍然检查
+	if err = Decode(map[string]string{"id": "1", "status": "x"}, obj); err != nil {
+		t.Errorf("err = %v", err)
+	}
+	if err = Decode(map[string]string{"status": "1"}, obj); !errors.Is(err, ErrMissingKey) {
+		t.Errorf("err = %v, want missing key id", err)
+	}
+}
+
+func TestApplyUnknown(t *testing.T) {
+	obj := &testModel{Name: "keep"}
+	src := map[string]string{"id": "1", "b": "1", "a": "2"}
+
+	var keys []string
+	if err := Apply(src, obj, OnUnknown(func(key string) { keys = append(keys, key) })); err != nil {
+		t.Fatalf("apply failed. err=%v", err)
+	}
+	if obj.Id != 1 || obj.Name != "keep" || len(keys) != 2 {
+		t.Errorf("obj = %+v keys=%v", obj, keys)
+	}
+
+	err := Apply(src, obj, DisallowUnknown())
+	var ue *UnknownKeyError
+	if !errors.As(err, &ue) || len(ue.Keys) != 2 || ue.Keys[0] != "a" {
+		t.Errorf("err = %v, want unknown keys [a b]", err)
+	}
+}
+
+func TestDecodeTag(t *testing.T) {
+	type tagged struct {
+		Id   int64  `json:"id" thrift:"item_id,1"`
+		Name string `json:"name"`
+	}
+	obj := &tagged{}
+	if err := Decode(map[string]string{"item_id": "7", "name": "x"}, obj, Tag("thrift")); err != nil {
+		t.Fatalf("decode failed. err=%v", err)
+	}
+	if obj.Id != 7 || obj.Name != "x" {
+		t.Errorf("obj = %+v", obj)
+	}
+}
+
+func TestDecodeNamedKey(t *testing.T) {
+	type key string
+	obj := &testModel{}
+	if err := Decode(map[key]string{"id": "3", "name": "x"}, obj, Strict()); err != nil {
+		t.Fatalf("decode failed. err=%v", err)
+	}
+	if obj.Id != 3 || obj.Name != "x" {
+		t.Errorf("obj = %+v", obj)
+	}
+
+	err := Decode(map[key]string{"name": "x"}, obj, Strict())
+	if !errors.Is(err, ErrMissingKey) {
+		t.Errorf("err = %v, want missing key id", err)
+	}
+}
+
+func TestDecodeTime(t *testing.T) {
+	type timed struct {
+		Timeout  time.Duration  `json:"timeout"`
+		Interval *time.Duration `json:"interval"`
+		Created  time.Time      `json:"created"`
+	}
+	obj := &timed{}
+	src := map[string]interface{}{"timeout": "3s", "interval": float64(1000), "created": "2024-01-02T03:04:05Z"}
+	if err := Decode(src, obj, Strict()); err != nil {
+		t.Fatalf("decode failed. err=%v", err)
+	}
+	if obj.Timeout != 3*time.Second || obj.Interval == nil || *obj.Interval != time.Microsecond {
+		t.Errorf("obj = %+v", obj)
+	}
+	if !obj.Created.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("created = %v", obj.Created)
+	}
+
+	err := Decode(map[string]string{"timeout": "abc"}, obj, Strict())
+	var ke *KeyError
+	if !errors.As(err, &ke) || ke.Key != "timeout" {
+		t.Errorf("err = %v, want key error timeout", err)
+	}
+}
+
+type testAuthor struct {
+	Id   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type testPublisher struct {
+	City string `json:"city"`
+}
+
+func (p *testPublisher) Default() {
+	p.City = "unknown"
+}
+
+type testChapter struct {
+	Title string `json:"title"`
+	Pages int32  `json:"pages"`
+}
+
+type testFlat struct {
+	Title     string            `json:"title" m2s:"name"`
+	Skip      string            `json:"skip" m2s:"-"`
+	Author    testAuthor        `json:"author" m2s:",prefix=author."`
+	Publisher *testPublisher    `json:"publisher" m2s:",prefix=publisher_"`
+	Chapters  []*testChapter    `json:"chapters" m2s:",prefix=chapters."`
+	Tags      []string          `json:"tags" m2s:",prefix=tags."`
+	Ids       []int64           `json:"ids" m2s:",sep=,"`
+	Ext       map[string]string `json:"ext" m2s:",json"`
+	Meta      map[string]string `json:"meta"`
+}
+
+func TestDecodeFlat(t *testing.T) {
+	src := map[string]string{
+		"name":             "go",
+		"author.id":        "7",
+		"publisher_city":   "bj",
+		"chapters.1.title": "two",
+		"chapters.0.pages": "12",
+		"tags.1":           "b",
+		"ids":              "1, 2,3",
+		"ext":              `{"k":"v"}`,
+	}
+	obj := &testFlat{}
+	if err := Decode(src, obj, Strict(), DisallowUnknown()); err != nil {
+		t.Fatalf("decode failed. err=%v", err)
+	}
+	if obj.Title != "go" || obj.Skip != "" || obj.Author.Id != 7 || obj.Publisher == nil || obj.Publisher.City != "bj" {
+		t.Errorf("obj = %+v", obj)
+	}
+	if len(obj.Chapters) != 2 || obj.Chapters[0].Pages != 12 || obj.Chapters[1].Title != "two" {
+		t.Errorf("chapters = %+v", obj.Chapters)
+	}
+	if len(obj.Tags) != 2 || obj.Tags[1] != "b" || len(obj.Ids) != 3 || obj.Ids[2] != 3 || obj.Ext["k"] != "v" {
+		t.Errorf("obj = %+v", obj)
+	}
+
+	// 保留已有的元素
+	if err := Apply(map[string]string{"chapters.1.pages": "20"}, obj, Strict()); err != nil {
+		t.Fatalf("apply failed. err=%v", err)
+	}
+	if len(obj.Chapters) != 2 || obj.Chapters[1].Title != "two" || obj.Chapters[1].Pages != 20 {
+		t.Errorf("chapters = %+v", obj.Chapters)
+	}
+
+	// 指针只在 key 存在时创建，并调用 Default()
+	obj = &testFlat{}
+	if err := Decode(map[string]string{"publisher_xx": "1"}, obj); err != nil || obj.Publisher != nil {
+		t.Errorf("publisher = %+v err=%v", obj.Publisher, err)
+	}
+	if err := Decode(map[string]string{"chapters.0.title": "one"}, obj, Strict()); err != nil || obj.Chapters[0].Title != "one" {
+		t.Errorf("chapters = %+v err=%v", obj.Chapters, err)
+	}
+}
+
+func TestDecodeFlatErrors(t *testing.T) {
+	obj := &testFlat{}
+	err := Decode(map[string]string{"ids": "1,x"}, obj, Strict())
+	var ee *ElemError
+	if !errors.As(err, &ee) || ee.Index != 1 {
+		t.Errorf("err = %v, want elem error 1", err)
+	}
+
+	// 下标超过 MaxIndex: 严格模式返回错误，否则作为未知的 key
+	err = Decode(map[string]string{"tags.3": "x"}, obj, Strict(), MaxIndex(3))
+	if !errors.Is(err, ErrIndexRange) || !errors.As(err, &ee) || ee.Index != 3 {
+		t.Errorf("err = %v, want index out of range", err)
+	}
+	var ue *UnknownKeyError
+	err = Decode(map[string]string{"tags.3": "x"}, obj, MaxIndex(3), DisallowUnknown())
+	if !errors.As(err, &ue) || len(ue.Keys) != 1 || ue.Keys[0] != "tags.3" {
+		t.Errorf("err = %v, want unknown tags.3", err)
+	}
+
+	// 不支持的字段类型不能被忽略
+	err = Decode(map[string]string{"meta": "x"}, obj)
+	var ke *KeyError
+	if !errors.As(err, &ke) || ke.Key != "meta" || !errors.Is(err, ErrUnsupportedField) {
+		t.Errorf("err = %v, want unsupported meta", err)
+	}
+}
+
+func TestDecodeFlatten(t *testing.T) {
+	type book struct {
+		Author   testAuthor    `json:"author"`
+		Chapters []testChapter `json:"chapters"`
+		Ids      []int64       `json:"ids" m2s:",sep=|"`
+	}
+	obj := &book{}
+	src := map[string]string{"author_name": "x", "chapters_0_title": "one", "ids": "1|2"}
+	if err := Decode(src, obj, Flatten("_"), Strict(), DisallowUnknown()); err != nil {
+		t.Fatalf("decode failed. err=%v", err)
+	}
+	if obj.Author.Name != "x" || len(obj.Chapters) != 1 || obj.Chapters[0].Title != "one" || len(obj.Ids) != 2 {
+		t.Errorf("obj = %+v", obj)
+	}
+
+	// 没有 Flatten 时嵌套结构体不支持
+	if err := Decode(map[string]string{"author": "x"}, obj); !errors.Is(err, ErrUnsupportedField) {
+		t.Errorf("err = %v, want unsupported author", err)
+	}
+}
+
+func TestDecodeMultiValues(t *testing.T) {
+	type request struct {
+		Keyword  string       `form:"q"`
+		PageSize *int32       `query:"page_size"`
+		Tags     []string     `form:"tag"`
+		Statuses []testStatus `form:"status"`
+	}
+	obj := &request{}
+	src := url.Values{"q": {"go", "rust"}, "page_size": {"20"}, "tag": {"a", "b"}, "status": {"1", "2"}}
+	if err := Decode(src, obj, Strict(), DisallowUnknown()); err != nil {
+		t.Fatalf("decode failed. err=%v", err)
+	}
+	if obj.Keyword != "go" || obj.PageSize == nil || *obj.PageSize != 20 || len(obj.Tags) != 2 || len(obj.Statuses) != 2 || obj.Statuses[1] != 2 {
+		t.Errorf("obj = %+v", obj)
+	}
+
+	// 值为空切片时视为不存在
+	if err := Decode(url.Values{"q": {}}, obj, Strict()); err != nil || obj.Keyword != "" {
+		t.Errorf("obj = %+v err=%v", obj, err)
+	}
+
+	type meta struct {
+		TraceId string `header:"x-trace-id"`
+		UserId  int64  `header:"x-user-id"`
+	}
+	header := http.Header{}
+	header.Set("x-trace-id", "abc")
+	header.Set("X-User-Id", "7")
+	m := &meta{}
+	if err := Decode(header, m, Strict(), DisallowUnknown()); err != nil {
+		t.Fatalf("decode failed. err=%v", err)
+	}
+	if m.TraceId != "abc" || m.UserId != 7 {
+		t.Errorf("meta = %+v", m)
+	}
+}
+
+func TestDecodeThriftName(t *testing.T) {
+	type item struct {
+		Id    int64  `thrift:"item_id,1,required" json:"id"`
+		Title string `thrift:"title,2" json:"name"`
+	}
+	obj := &item{}
+	if err := Decode(map[string]string{"item_id": "1", "title": "x"}, obj, DisallowUnknown()); err != nil {
+		t.Fatalf("decode failed. err=%v", err)
+	}
+	if obj.Id != 1 || obj.Title != "x" {
+		t.Errorf("obj = %+v", obj)
+	}
+
+	// 指定了 tag 时使用指定的 tag
+	if err := Decode(map[string]string{"item_id": "1", "name": "y"}, obj, Tag("json")); !errors.Is(err, ErrMissingKey) {
+		t.Errorf("err = %v, want missing key id", err)
+	}
+}
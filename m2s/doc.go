@@ -0,0 +1,2 @@
+// Package m2s 是 map2struct 生成代码依赖的运行时库
+package m2s
@@ -0,0 +1,63 @@
+package m2s
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// EnvVar 生成的 LoadXxxFromEnv 读取的环境变量
+type EnvVar struct {
+	Name       string // 环境变量名，包含前缀
+	Field      string // 对应的字段，嵌套结构体为 Db.Host
+	Type       string
+	Default    string
+	Required   bool
+	AllowEmpty bool // 空字符串作为有效值，否则与未设置相同
+}
+
+// Lookup 读取环境变量，不存在或者为空（没有设置 AllowEmpty）时使用默认值
+func (v *EnvVar) Lookup() (string, bool) {
+	if val, ok := os.LookupEnv(v.Name); ok && (val != "" || v.AllowEmpty) {
+		return val, true
+	}
+	if v.Default != "" {
+		return v.Default, true
+	}
+	return "", false
+}
+
+// EnvName 拼接前缀和变量名: EnvName("APP", "DB_HOST") = "APP_DB_HOST"
+func EnvName(prefix, name string) string {
+	prefix = strings.TrimSuffix(prefix, "_")
+	if prefix == "" {
+		return name
+	}
+	return prefix + "_" + name
+}
+
+// CheckEnv 检查必须的环境变量，返回所有缺少的变量
+func CheckEnv(vars []EnvVar) error {
+	var missing []string
+	for idx := range vars {
+		if _, ok := vars[idx].Lookup(); !ok && vars[idx].Required {
+			missing = append(missing, vars[idx].Name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w. env=%s", ErrMissingKey, strings.Join(missing, ","))
+	}
+	return nil
+}
+
+// PrintEnvVars 打印需要的环境变量列表
+func PrintEnvVars(w io.Writer, vars []EnvVar) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tTYPE\tDEFAULT\tREQUIRED\tFIELD")
+	for _, v := range vars {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%t\t%s\n", v.Name, v.Type, v.Default, v.Required, v.Field)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,60 @@
+package m2s
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestEnvVar(t *testing.T) {
+	if name := EnvName("APP_", "DB_HOST"); name != "APP_DB_HOST" {
+		t.Errorf("EnvName = %s", name)
+	}
+	if name := EnvName("", "DB_HOST"); name != "DB_HOST" {
+		t.Errorf("EnvName = %s", name)
+	}
+
+	os.Setenv("M2S_TEST_HOST", "db")
+	defer os.Unsetenv("M2S_TEST_HOST")
+	vars := []EnvVar{
+		{Name: "M2S_TEST_HOST", Field: "Host", Type: "string", Required: true},
+		{Name: "M2S_TEST_PORT", Field: "Port", Type: "int", Default: "3306"},
+		{Name: "M2S_TEST_USER", Field: "User", Type: "string", Required: true},
+	}
+	if val, ok := vars[0].Lookup(); !ok || val != "db" {
+		t.Errorf("lookup env. val=%s ok=%v", val, ok)
+	}
+	if val, ok := vars[1].Lookup(); !ok || val != "3306" {
+		t.Errorf("lookup default. val=%s ok=%v", val, ok)
+	}
+
+	err := CheckEnv(vars)
+	if !errors.Is(err, ErrMissingKey) || !strings.Contains(err.Error(), "M2S_TEST_USER") {
+		t.Errorf("check env. err=%v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err = PrintEnvVars(buf, vars); err != nil || !strings.Contains(buf.String(), "M2S_TEST_PORT") {
+		t.Errorf("print env. out=%s err=%v", buf, err)
+	}
+}
+
+func TestEnvVarEmpty(t *testing.T) {
+	os.Setenv("M2S_TEST_EMPTY", "")
+	defer os.Unsetenv("M2S_TEST_EMPTY")
+
+	v := EnvVar{Name: "M2S_TEST_EMPTY", Default: "x"}
+	if val, ok := v.Lookup(); !ok || val != "x" {
+		t.Errorf("empty env should use default. val=%q ok=%v", val, ok)
+	}
+	v.Default = ""
+	if _, ok := v.Lookup(); ok {
+		t.Errorf("empty env should be unset")
+	}
+	v.AllowEmpty, v.Default = true, "x"
+	if val, ok := v.Lookup(); !ok || val != "" {
+		t.Errorf("allow empty. val=%q ok=%v", val, ok)
+	}
+}
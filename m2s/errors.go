@@ -0,0 +1,53 @@
+package m2s
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeyError 生成代码在严格模式下，某个 key 的值转换失败时返回
+type KeyError struct {
+	Key string
+	Err error
+}
+
+func (e *KeyError) Error() string {
+	return fmt.Sprintf("convert key failed. key=%s err=%v", e.Key, e.Err)
+}
+
+func (e *KeyError) Unwrap() error {
+	return e.Err
+}
+
+// UnknownKeyError 源 map 中存在结构体没有定义的 key
+type UnknownKeyError struct {
+	Keys []string
+}
+
+func (e *UnknownKeyError) Error() string {
+	return fmt.Sprintf("unknown keys. keys=%s", strings.Join(e.Keys, ","))
+}
+
+// ErrMissingKey 严格模式下 required 字段对应的 key 不存在
+var ErrMissingKey = errors.New("missing required key")
+
+// ErrIndexRange 带下标的 key 中下标超过生成时的 -maxindex
+var ErrIndexRange = errors.New("index out of range")
+
+// ErrUnsupportedField key 对应的字段类型生成代码不支持，需要手动处理
+var ErrUnsupportedField = errors.New("field type not supported")
+
+// ElemError 切片字段中第 Index 个元素（从 0 开始）转换失败
+type ElemError struct {
+	Index int
+	Err   error
+}
+
+func (e *ElemError) Error() string {
+	return fmt.Sprintf("convert element failed. index=%d err=%v", e.Index, e.Err)
+}
+
+func (e *ElemError) Unwrap() error {
+	return e.Err
+}
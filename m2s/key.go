@@ -0,0 +1,46 @@
+package m2s
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// SortKeys 排序并去掉重复的 key，合并多个 src 的未知 key 时使用
+func SortKeys(keys []string) []string {
+	sort.Strings(keys)
+	uniq := keys[:0]
+	for _, key := range keys {
+		if len(uniq) == 0 || key != uniq[len(uniq)-1] {
+			uniq = append(uniq, key)
+		}
+	}
+	return uniq
+}
+
+// KeyIndex 解析带下标的 key，比如 prefix 为 "items."、sep 为 "." 时: "items.3.title" 和 "items.3" 返回 3。
+// 下标之后的部分必须为空，或者为 sep 加上 known 认可的元素字段（known 为 nil 时只允许为空）；不匹配时 ok 为 false。
+// 不检查下标的上限，超过 math.MaxInt32 时返回 math.MaxInt32，由调用方与生成时的 -maxindex 比较
+func KeyIndex(key, prefix, sep string, known func(key string) bool) (idx int, ok bool) {
+	if !strings.HasPrefix(key, prefix) {
+		return 0, false
+	}
+	key = key[len(prefix):]
+	end := 0
+	for end < len(key) && key[end] >= '0' && key[end] <= '9' {
+		if idx = idx*10 + int(key[end]-'0'); idx > math.MaxInt32 {
+			idx = math.MaxInt32
+		}
+		end++
+	}
+	// 不允许空下标和前导 0
+	if end == 0 || (end > 1 && key[0] == '0') {
+		return 0, false
+	}
+	if rest := key[end:]; rest != "" {
+		if known == nil || !strings.HasPrefix(rest, sep) || !known(rest[len(sep):]) {
+			return 0, false
+		}
+	}
+	return idx, true
+}
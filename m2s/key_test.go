@@ -0,0 +1,73 @@
+package m2s
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestKeyIndex(t *testing.T) {
+	// 元素字段: title, pages, 以及带下标的 notes.0
+	known := func(key string) bool {
+		switch key {
+		case "title", "pages":
+			return true
+		}
+		_, ok := KeyIndex(key, "notes.", ".", nil)
+		return ok
+	}
+	cases := []struct {
+		key string
+		idx int
+		ok  bool
+	}{
+		{"items.0.title", 0, true},
+		{"items.12.title", 12, true},
+		{"items.3", 3, true},
+		{"items.3.notes.2", 3, true},
+		{"items.", 0, false},
+		{"items.01.title", 0, false},
+		{"items.-1.title", 0, false},
+		{"items.x.title", 0, false},
+		{"items.100", 100, true},
+		{"items.99999999999999999999.title", math.MaxInt32, true},
+		{"items", 0, false},
+		{"author.name", 0, false},
+		{"items.3.garbage", 0, false},
+		{"items.3.", 0, false},
+		{"items.3title", 0, false},
+		{"items.3_title", 0, false},
+		{"items.3.title.x", 0, false},
+		{"items.3.notes.x", 0, false},
+		{"items.3.notes.2.x", 0, false},
+	}
+	for _, c := range cases {
+		idx, ok := KeyIndex(c.key, "items.", ".", known)
+		if idx != c.idx || ok != c.ok {
+			t.Errorf("key=%s got=(%d, %v) want=(%d, %v)", c.key, idx, ok, c.idx, c.ok)
+		}
+	}
+
+	// 基本类型的元素，下标之后不能有其他内容
+	for key, want := range map[string]bool{"tags.1": true, "tags.1.title": false, "tags.1x": false} {
+		if _, ok := KeyIndex(key, "tags.", ".", nil); ok != want {
+			t.Errorf("key=%s ok=%v want=%v", key, ok, want)
+		}
+	}
+}
+
+func TestSortKeys(t *testing.T) {
+	cases := []struct {
+		keys []string
+		want []string
+	}{
+		{nil, nil},
+		{[]string{"b", "a", "b", "c", "a"}, []string{"a", "b", "c"}},
+		{[]string{"x", "x"}, []string{"x"}},
+	}
+	for _, c := range cases {
+		if got := SortKeys(c.keys); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("SortKeys = %v, want %v", got, c.want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package m2s
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	// ErrNotRegistered 没有注册 src 类型到 obj 类型的转换函数
+	ErrNotRegistered = errors.New("converter not registered")
+	// ErrRegistered src 类型到 obj 类型的转换函数已经注册过
+	ErrRegistered = errors.New("converter already registered")
+)
+
+// Converter 生成代码（map2struct -register）在 init 时注册的转换函数
+type Converter struct {
+	MapTo func(src interface{}) (interface{}, error)   // genMapToXxx
+	Apply func(src interface{}, obj interface{}) error // genApplyXxx
+}
+
+type converterKey struct {
+	src reflect.Type
+	obj reflect.Type
+}
+
+var (
+	convMu     sync.RWMutex
+	converters = map[converterKey]*Converter{}
+)
+
+// Register 注册转换函数，src 和 obj 只用于确定类型，比如:
+//
+//	m2s.Register((map[string]string)(nil), (*model.ApiBookInfo)(nil), &m2s.Converter{...})
+//
+// 同一对类型重复注册时保留先注册的函数，比如两个包都使用 -register 生成了相同类型的函数
+func Register(src interface{}, obj interface{}, conv *Converter) {
+	_ = RegisterE(src, obj, conv)
+}
+
+// RegisterE 与 Register 相同，同一对类型已经注册过时不替换，返回 ErrRegistered
+func RegisterE(src interface{}, obj interface{}, conv *Converter) error {
+	key := converterKey{src: reflect.TypeOf(src), obj: reflect.TypeOf(obj)}
+	convMu.Lock()
+	defer convMu.Unlock()
+	if _, ok := converters[key]; ok {
+		return fmt.Errorf("%w. src=%v obj=%v", ErrRegistered, key.src, key.obj)
+	}
+	converters[key] = conv
+	return nil
+}
+
+// Lookup 查找 src 类型到 obj 类型的转换函数
+func Lookup(src interface{}, obj interface{}) (*Converter, bool) {
+	key := converterKey{src: reflect.TypeOf(src), obj: reflect.TypeOf(obj)}
+	convMu.RLock()
+	defer convMu.RUnlock()
+	conv, ok := converters[key]
+	return conv, ok
+}
+
+// Fallback 没有注册转换函数时使用反射（Decode/Apply），否则返回 ErrNotRegistered
+func Fallback() Option {
+	return func(o *options) { o.fallback = true }
+}
+
+// DecodeInto 使用注册的 genApplyXxx 转换到已有对象 obj（PATCH 语义），不使用反射；
+// 注册的函数行为由生成参数决定，opts 只对 Fallback 的反射转换生效
+func DecodeInto(src interface{}, obj interface{}, opts ...Option) error {
+	if conv, ok := Lookup(src, obj); ok && conv.Apply != nil {
+		return conv.Apply(src, obj)
+	}
+	if o := newOptions(opts); o.fallback {
+		return Apply(src, obj, opts...)
+	}
+	return fmt.Errorf("%w. src=%T obj=%T", ErrNotRegistered, src, obj)
+}
+
+// DecodeNew 使用注册的 genMapToXxx 创建新对象，typ 为目标类型的指针，比如 (*model.ApiBookInfo)(nil)；
+// Fallback 时使用反射的 Decode
+func DecodeNew(src interface{}, typ interface{}, opts ...Option) (interface{}, error) {
+	if conv, ok := Lookup(src, typ); ok && conv.MapTo != nil {
+		return conv.MapTo(src)
+	}
+	if o := newOptions(opts); o.fallback {
+		rt := reflect.TypeOf(typ)
+		if rt == nil || rt.Kind() != reflect.Ptr {
+			return nil, fmt.Errorf("typ must be a pointer type. type=%T", typ)
+		}
+		obj := reflect.New(rt.Elem()).Interface()
+		if err := Decode(src, obj, opts...); err != nil {
+			return nil, err
+		}
+		return obj, nil
+	}
+	return nil, fmt.Errorf("%w. src=%T obj=%T", ErrNotRegistered, src, typ)
+}
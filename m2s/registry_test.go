@@ -0,0 +1,59 @@
+package m2s
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testRegModel struct {
+	Name string `json:"name"`
+}
+
+func TestRegistry(t *testing.T) {
+	t.Cleanup(func() {
+		convMu.Lock()
+		defer convMu.Unlock()
+		delete(converters, converterKey{src: reflect.TypeOf(map[string]string(nil)), obj: reflect.TypeOf((*testRegModel)(nil))})
+	})
+	Register((map[string]string)(nil), (*testRegModel)(nil), &Converter{
+		MapTo: func(src interface{}) (interface{}, error) {
+			return &testRegModel{Name: "gen:" + src.(map[string]string)["name"]}, nil
+		},
+		Apply: func(src interface{}, obj interface{}) error {
+			obj.(*testRegModel).Name = "gen:" + src.(map[string]string)["name"]
+			return nil
+		},
+	})
+
+	src := map[string]string{"name": "go"}
+	obj, err := DecodeNew(src, (*testRegModel)(nil))
+	if err != nil || obj.(*testRegModel).Name != "gen:go" {
+		t.Errorf("decode new. obj=%+v err=%v", obj, err)
+	}
+	got := &testRegModel{}
+	if err = DecodeInto(src, got); err != nil || got.Name != "gen:go" {
+		t.Errorf("decode into. obj=%+v err=%v", got, err)
+	}
+
+	// 未注册的类型
+	other := map[string]interface{}{"name": "go"}
+	if err = DecodeInto(other, got); !errors.Is(err, ErrNotRegistered) {
+		t.Errorf("want ErrNotRegistered. err=%v", err)
+	}
+	if err = DecodeInto(other, got, Fallback()); err != nil || got.Name != "go" {
+		t.Errorf("fallback decode into. obj=%+v err=%v", got, err)
+	}
+	if obj, err = DecodeNew(other, (*testRegModel)(nil), Fallback()); err != nil || obj.(*testRegModel).Name != "go" {
+		t.Errorf("fallback decode new. obj=%+v err=%v", obj, err)
+	}
+
+	// 重复注册保留先注册的函数
+	Register((map[string]string)(nil), (*testRegModel)(nil), &Converter{})
+	if err = RegisterE((map[string]string)(nil), (*testRegModel)(nil), &Converter{}); !errors.Is(err, ErrRegistered) {
+		t.Errorf("want ErrRegistered. err=%v", err)
+	}
+	if obj, err = DecodeNew(src, (*testRegModel)(nil)); err != nil || obj.(*testRegModel).Name != "gen:go" {
+		t.Errorf("first registration should win. obj=%+v err=%v", obj, err)
+	}
+}
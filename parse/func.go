@@ -1,40 +1,130 @@
 package parse
 
 import (
+	"fmt"
 	"go/ast"
 	"log"
+	"strings"
 )
 
 type FunctionV2 struct {
 	Name        string
 	FuncAst     *ast.FuncType `json:"-"`
 	InputParam  *MapType
+	InputParams []*MapType    // 所有 map 类型的参数，多个时按顺序合并
+	Params      []*ObjectType // 其他参数，比如: *sql.Rows
 	OutputType  *ObjectType
 	OutputParam *StructV2
+	Directives  []*Directive // 函数注释中的 //map2struct:xxx 指令
+	File        string       // 声明所在的文件路径
+}
+
+const directivePrefix = "//map2struct:"
+
+// Directive 函数注释中的指令，比如: //map2struct:new NewConfig
+type Directive struct {
+	Name string
+	Args string
 }
 
 type MapType struct {
+	Name             string // 参数名
 	KeyType          string
-	ValueType        string
+	ValueType        string // 多值 map 为元素类型
 	IsValueInterface bool
+	Multi            bool   // 多值 map: url.Values, http.Header, map[string][]string
+	TypeName         string // 命名的 map 类型，比如: url.Values
+}
+
+// 多值 map 的命名类型，key 都是 string，value 都是 []string
+var multiMapTypes = map[string]bool{
+	"url.Values":  true,
+	"http.Header": true,
 }
 
 type ObjectType struct {
 	Name     string
 	Pointer  bool
+	Slice    bool
 	Package  string
 	TypeName string
 }
 
+func parseDirectives(doc *ast.CommentGroup) []*Directive {
+	if doc == nil {
+		return nil
+	}
+
+	var list []*Directive
+	for _, cm := range doc.List {
+		if !strings.HasPrefix(cm.Text, directivePrefix) {
+			continue
+		}
+		text := strings.TrimPrefix(cm.Text, directivePrefix)
+		ss := strings.SplitN(text, " ", 2)
+		dir := &Directive{Name: ss[0]}
+		if len(ss) > 1 {
+			dir.Args = strings.TrimSpace(ss[1])
+		}
+		list = append(list, dir)
+	}
+	return list
+}
+
+// Directive 返回最后一个名为 name 的指令参数，不存在返回空
+func (fi *FunctionV2) Directive(name string) string {
+	args := ""
+	for _, dir := range fi.Directives {
+		if dir.Name == name {
+			args = dir.Args
+		}
+	}
+	return args
+}
+
+// HasDirective 是否存在名为 name 的指令
+func (fi *FunctionV2) HasDirective(name string) bool {
+	for _, dir := range fi.Directives {
+		if dir.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// String map 的类型表达式，比如: map[string]string, url.Values
+func (mt *MapType) String() string {
+	if mt.TypeName != "" {
+		return mt.TypeName
+	}
+	if mt.Multi {
+		return fmt.Sprintf("map[%s][]%s", mt.KeyType, mt.ValueType)
+	}
+	return fmt.Sprintf("map[%s]%s", mt.KeyType, mt.ValueType)
+}
+
+// IsHeader http.Header 的 key 是规范化（CanonicalHeaderKey）的
+func (mt *MapType) IsHeader() bool {
+	return mt.TypeName == "http.Header"
+}
+
 func parseMapType(idt *ast.Field) *MapType {
 	switch t := idt.Type.(type) {
 	case *ast.MapType:
-		key := t.Key.(*ast.Ident)
+		key, ok := t.Key.(*ast.Ident)
+		if !ok {
+			return nil
+		}
 		mt := &MapType{
 			KeyType: key.Name,
 		}
 
-		switch vt := t.Value.(type) {
+		vt := t.Value
+		if at, ok := vt.(*ast.ArrayType); ok && at.Len == nil {
+			mt.Multi = true
+			vt = at.Elt
+		}
+		switch vt := vt.(type) {
 		case *ast.Ident:
 			mt.ValueType = vt.Name
 		case *ast.InterfaceType:
@@ -42,6 +132,15 @@ func parseMapType(idt *ast.Field) *MapType {
 			mt.IsValueInterface = true
 		}
 		return mt
+
+	case *ast.SelectorExpr:
+		x, ok := t.X.(*ast.Ident)
+		if !ok {
+			return nil
+		}
+		if name := x.Name + "." + t.Sel.Name; multiMapTypes[name] {
+			return &MapType{KeyType: "string", ValueType: "string", Multi: true, TypeName: name}
+		}
 	}
 	return nil
 }
@@ -97,6 +97,26 @@ func GetModDetail(pkgName string) (*Module, error) {
 	return m, nil
 }
 
+// ListPackageDirs 通过 go list 展开包，支持 ./... 和 import 路径，只返回有 go 文件（非单测）的目录
+func ListPackageDirs(patterns []string) ([]string, error) {
+	args := append([]string{"list", "-f", "{{if .GoFiles}}{{.Dir}}{{end}}"}, patterns...)
+	data, err := exec.Command("go", args...).Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok {
+			err = fmt.Errorf("%v: %s", err, strings.TrimSpace(string(ee.Stderr)))
+		}
+		return nil, fmt.Errorf("go list failed. patterns=%v err=%v", patterns, err)
+	}
+
+	var dirs []string
+	for _, dir := range strings.Split(string(data), "\n") {
+		if dir = strings.TrimSpace(dir); dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs, nil
+}
+
 func GetPkgAbsPath(pkgPath string) string {
 	if pkgPath == "C" {
 		return ""
@@ -9,6 +9,7 @@ import (
 	"io/fs"
 	"log"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
@@ -60,7 +61,7 @@ func (pp *DelayPkgParser) astParseDir(dir string, mode parser.Mode) (string, *as
 }
 
 func (pp *DelayPkgParser) parseDir(srcDir string, pkgPath string) (*PackageV2, error) {
-	pkgName, pkgAst, err := pp.astParseDir(srcDir, 0)
+	pkgName, pkgAst, err := pp.astParseDir(srcDir, parser.ParseComments)
 	if err != nil || pkgAst == nil {
 		log.Fatalf("parse dir failed. dir=%s err=%v", srcDir, err)
 		return nil, err
@@ -145,23 +146,36 @@ func (p *PackageV2) GetImportPkg(pkgName string) (*PackageV2, error) {
 }
 
 func (p *PackageV2) FindFuncList(prefix string) (map[string]*FunctionV2, error) {
+	return p.FindFuncMatch(func(name string) bool {
+		return strings.HasPrefix(name, prefix)
+	})
+}
+
+// FindFuncMatch 查找函数名满足 match 的函数，比如按正则匹配
+func (p *PackageV2) FindFuncMatch(match func(name string) bool) (map[string]*FunctionV2, error) {
 	if p.PackageAst == nil {
 		return nil, fmt.Errorf("package not parsed. pkg=%s", p.Name)
 	}
 
 	fnList := map[string]*FunctionV2{}
-	ast.Inspect(p.PackageAst, func(node ast.Node) bool {
-		switch idt := node.(type) {
-		case *ast.FuncDecl:
-			fi, err := p.parseFuncDecl(idt, prefix)
-			if fi == nil || err != nil {
-				return true
-			} else {
-				fnList[fi.Name] = fi
+	for _, fileName := range p.fileNames() {
+		ast.Inspect(p.PackageAst.Files[fileName], func(node ast.Node) bool {
+			switch idt := node.(type) {
+			case *ast.FuncDecl:
+				if !match(idt.Name.Name) {
+					return true
+				}
+				fi, err := p.parseFuncDecl(idt, "")
+				if fi == nil || err != nil {
+					return true
+				} else {
+					fi.File = fileName
+					fnList[fi.Name] = fi
+				}
 			}
-		}
-		return true
-	})
+			return true
+		})
+	}
 	return fnList, nil
 }
 
@@ -192,6 +206,140 @@ func (p *PackageV2) FindStruct(stName string) (*StructV2, error) {
 	return si, nil
 }
 
+// FindConstructor 查找结构体的构造函数: func New<stName>() *<stName>，不存在返回空
+func (p *PackageV2) FindConstructor(stName string) string {
+	if ctorName := "New" + stName; p.HasFactory(ctorName, stName) {
+		return ctorName
+	}
+	return ""
+}
+
+// HasFactory 是否存在无参函数 func <funcName>() *<stName>
+func (p *PackageV2) HasFactory(funcName, stName string) bool {
+	if p.PackageAst == nil {
+		return false
+	}
+
+	found := false
+	ast.Inspect(p.PackageAst, func(node ast.Node) bool {
+		fd, ok := node.(*ast.FuncDecl)
+		if !ok || fd.Recv != nil || fd.Name.Name != funcName {
+			return !found
+		}
+		ft := fd.Type
+		if ft.Params != nil && len(ft.Params.List) > 0 {
+			return false
+		}
+		if ft.Results == nil || len(ft.Results.List) != 1 {
+			return false
+		}
+		if st, ok := ft.Results.List[0].Type.(*ast.StarExpr); ok {
+			if idt, ok := st.X.(*ast.Ident); ok && idt.Name == stName {
+				found = true
+			}
+		}
+		return false
+	})
+	return found
+}
+
+// HasInitMethod 是否存在无参、无返回值的方法 func (*<stName>) <method>()，比如 Default()
+func (p *PackageV2) HasInitMethod(stName, method string) bool {
+	if p.PackageAst == nil {
+		return false
+	}
+
+	for _, file := range p.sortedFiles() {
+		for _, decl := range file.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || fd.Recv == nil || fd.Name.Name != method || len(fd.Recv.List) != 1 {
+				continue
+			}
+			st, ok := fd.Recv.List[0].Type.(*ast.StarExpr)
+			if !ok {
+				continue
+			}
+			if idt, ok := st.X.(*ast.Ident); !ok || idt.Name != stName {
+				continue
+			}
+			ft := fd.Type
+			if (ft.Params == nil || len(ft.Params.List) == 0) && (ft.Results == nil || len(ft.Results.List) == 0) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasVar 包中是否定义了全局变量 varName，比如 proto 枚举的 Xxx_value
+func (p *PackageV2) HasVar(varName string) bool {
+	if p.PackageAst == nil {
+		return false
+	}
+
+	found := false
+	ast.Inspect(p.PackageAst, func(node ast.Node) bool {
+		switch spec := node.(type) {
+		case *ast.FuncDecl:
+			return false
+		case *ast.ValueSpec:
+			for _, name := range spec.Names {
+				if name.Name == varName {
+					found = true
+				}
+			}
+			return false
+		}
+		return !found
+	})
+	return found
+}
+
+// FindMethodTypes 查找定义了方法 methodName 的类型，比如 proto oneof 的包装类型
+func (p *PackageV2) FindMethodTypes(methodName string) []string {
+	if p.PackageAst == nil {
+		return nil
+	}
+
+	var types []string
+	for _, file := range p.sortedFiles() {
+		for _, decl := range file.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || fd.Recv == nil || fd.Name.Name != methodName || len(fd.Recv.List) != 1 {
+				continue
+			}
+			recv := fd.Recv.List[0].Type
+			if st, ok := recv.(*ast.StarExpr); ok {
+				recv = st.X
+			}
+			if idt, ok := recv.(*ast.Ident); ok {
+				types = append(types, idt.Name)
+			}
+		}
+	}
+	return types
+}
+
+// fileNames 排序后的文件路径
+func (p *PackageV2) fileNames() []string {
+	names := make([]string, 0, len(p.PackageAst.Files))
+	for name := range p.PackageAst.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sortedFiles 按文件名排序，保证遍历顺序稳定
+func (p *PackageV2) sortedFiles() []*ast.File {
+	names := p.fileNames()
+	files := make([]*ast.File, 0, len(names))
+	for _, name := range names {
+		files = append(files, p.PackageAst.Files[name])
+	}
+	return files
+}
+
 func (p *PackageV2) parseStructField(idt *ast.Field) *ObjectType {
 	ot := &ObjectType{}
 	if len(idt.Names) >= 1 {
@@ -218,6 +366,12 @@ func (p *PackageV2) parseStructField(idt *ast.Field) *ObjectType {
 		ot.TypeName = t.Sel.Name
 	case *ast.InterfaceType:
 		ot.TypeName = "interface"
+	case *ast.ArrayType:
+		// []*Model, 只处理切片
+		if t.Len == nil {
+			ot = p.parseStructField(&ast.Field{Names: idt.Names, Type: t.Elt})
+			ot.Slice = true
+		}
 	default:
 		log.Printf("unhandled type: %+v(%T)", t, t)
 	}
@@ -267,20 +421,35 @@ func (p *PackageV2) parseFuncDecl(idt *ast.FuncDecl, prefix string) (*FunctionV2
 	}
 
 	fi := &FunctionV2{
-		Name:    idt.Name.Name,
-		FuncAst: idt.Type,
+		Name:       idt.Name.Name,
+		FuncAst:    idt.Type,
+		Directives: parseDirectives(idt.Doc),
 	}
 
-	// 找到第一个map类型
+	// 所有 map 类型的参数，第一个为 InputParam
 	for idx, arg := range params.List {
 		if pArg := parseMapType(arg); pArg != nil {
-			fi.InputParam = pArg
-			break
+			names := []string{""}
+			if len(arg.Names) > 0 {
+				names = names[:0]
+				for _, name := range arg.Names {
+					names = append(names, name.Name)
+				}
+			}
+			for _, name := range names {
+				mt := *pArg
+				mt.Name = name
+				fi.InputParams = append(fi.InputParams, &mt)
+			}
 		} else {
 			pTyp := p.parseStructField(arg)
+			fi.Params = append(fi.Params, pTyp)
 			log.Printf("args[%d]=%+v", idx, pTyp)
 		}
 	}
+	if len(fi.InputParams) > 0 {
+		fi.InputParam = fi.InputParams[0]
+	}
 
 	fi.OutputType = p.parseStructField(returns.List[0])
 	depPkg, err := p.GetImportPkg(fi.OutputType.Package)
@@ -300,3 +469,37 @@ func (p *PackageV2) parseFuncDecl(idt *ast.FuncDecl, prefix string) (*FunctionV2
 	log.Printf("found function. fun=%s input=%+v output=%+v", fi.Name, fi.InputParam, fi.OutputType)
 	return fi, err
 }
+
+// NewTypeFunc 不需要函数声明，根据类型构造转换函数: func <name>(src <input>) (*<pkg>.<typeName>, error)
+// pkgPath 为类型所在包的 import 路径
+func (p *PackageV2) NewTypeFunc(name string, input *MapType, pkgPath, typeName string) (*FunctionV2, error) {
+	depPkg, err := p.parser.ParsePackage(pkgPath)
+	if err != nil || depPkg == nil {
+		return nil, fmt.Errorf("load package failed. pkg=%s err=%v", pkgPath, err)
+	}
+	if filepath.Clean(depPkg.SourcePath) == filepath.Clean(p.SourcePath) {
+		return nil, fmt.Errorf("type must be in another package. pkg=%s type=%s", pkgPath, typeName)
+	}
+	if path, ok := p.Imports[depPkg.Name]; !ok {
+		p.Imports[depPkg.Name] = pkgPath
+	} else if path != pkgPath {
+		return nil, fmt.Errorf("import name conflict. name=%s path=%s,%s", depPkg.Name, path, pkgPath)
+	}
+
+	st, err := depPkg.FindStruct(typeName)
+	if err != nil || st == nil {
+		return nil, fmt.Errorf("struct not found. pkg=%s type=%s err=%v", pkgPath, typeName, err)
+	}
+
+	mt := *input
+	mt.Name = "src"
+	fi := &FunctionV2{
+		Name:        name,
+		InputParam:  &mt,
+		InputParams: []*MapType{&mt},
+		OutputType:  &ObjectType{Pointer: true, Package: depPkg.Name, TypeName: typeName},
+		OutputParam: st,
+	}
+	log.Printf("found type. fun=%s input=%+v output=%+v", fi.Name, fi.InputParam, fi.OutputType)
+	return fi, nil
+}
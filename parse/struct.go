@@ -3,6 +3,8 @@ package parse
 import (
 	"go/ast"
 	"reflect"
+	"strconv"
+	"strings"
 
 	"github.com/adyzng/gotool/utils"
 )
@@ -26,9 +28,11 @@ type StructV2 struct {
 type TypeInfo struct {
 	Name     string
 	JsonName string
+	Tag      string // 完整的 struct tag
 	Package  string
 	Type     string
 	Kind     TypeKind
+	ElemPtr  bool // 切片元素为指针，比如: []*Item
 }
 
 func (si *StructV2) EnumField(iter func(fd *ast.Field) bool) {
@@ -48,10 +52,42 @@ func (si *StructV2) FieldName(fd *ast.Field) string {
 }
 
 func (si *StructV2) JsonName(fd *ast.Field) string {
+	return utils.GetJsonTagName(reflect.StructTag(si.FieldTag(fd)).Get("json"))
+}
+
+func (si *StructV2) FieldTag(fd *ast.Field) string {
 	if fd.Tag == nil {
 		return ""
 	}
-	return utils.GetJsonTagName(reflect.StructTag(fd.Tag.Value).Get("json"))
+	tag, err := strconv.Unquote(fd.Tag.Value)
+	if err != nil {
+		return ""
+	}
+	return tag
+}
+
+// IsProto protoc-gen-go 生成的结构体，字段带 protobuf:"..." 或 protobuf_oneof:"..." tag
+func (si *StructV2) IsProto() bool {
+	for _, fd := range si.AstInfo.Fields.List {
+		tag := reflect.StructTag(si.FieldTag(fd))
+		if _, ok := tag.Lookup("protobuf"); ok {
+			return true
+		}
+		if _, ok := tag.Lookup("protobuf_oneof"); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// IsThrift thrift/kitex 生成的结构体，字段带 thrift:"name,id,optional" tag
+func (si *StructV2) IsThrift() bool {
+	for _, fd := range si.AstInfo.Fields.List {
+		if _, ok := reflect.StructTag(si.FieldTag(fd)).Lookup("thrift"); ok {
+			return true
+		}
+	}
+	return false
 }
 
 func (si *StructV2) FieldType(fd *ast.Field) *TypeInfo {
@@ -59,6 +95,7 @@ func (si *StructV2) FieldType(fd *ast.Field) *TypeInfo {
 		Kind:     Unknown,
 		Name:     si.FieldName(fd),
 		JsonName: si.JsonName(fd),
+		Tag:      si.FieldTag(fd),
 	}
 
 	switch t := fd.Type.(type) {
@@ -80,12 +117,24 @@ func (si *StructV2) FieldType(fd *ast.Field) *TypeInfo {
 		ti.Type = t.Sel.Name
 		ti.Package = t.X.(*ast.Ident).Name
 
-		//case *ast.ArrayType:
-		//	ti.Kind = Array
-		//	switch elt := t.Elt.(type) {
-		//	case *ast.Ident:
-		//		ti.Type = elt.Name
-		//	}
+	case *ast.ArrayType:
+		// 基本类型和结构体的切片，Type 为元素类型
+		if t.Len == nil {
+			elt := t.Elt
+			if st, ok := elt.(*ast.StarExpr); ok {
+				ti.ElemPtr = true
+				elt = st.X
+			}
+			switch et := elt.(type) {
+			case *ast.Ident:
+				ti.Kind = Array
+				ti.Type = et.Name
+			case *ast.SelectorExpr:
+				ti.Kind = Array
+				ti.Type = et.Sel.Name
+				ti.Package = et.X.(*ast.Ident).Name
+			}
+		}
 	}
 
 	if ti.Package == "" && !utils.IsBaseType(ti.Type) {
@@ -94,8 +143,95 @@ func (si *StructV2) FieldType(fd *ast.Field) *TypeInfo {
 	return ti
 }
 
+// KeyName 字段对应的 map key：优先使用 tagName 指定的 tag，其次 json tag，最后字段名
+// 返回空表示忽略该字段（tag 为 "-"）
+func (ti *TypeInfo) KeyName(tagName string) string {
+	for _, name := range []string{tagName, "json"} {
+		if name == "" {
+			continue
+		}
+		if val, ok := reflect.StructTag(ti.Tag).Lookup(name); ok {
+			if val == "-" {
+				return ""
+			}
+			if key := utils.GetJsonTagName(val); key != "" {
+				return key
+			}
+		}
+	}
+	return ti.Name
+}
+
+// HasTag 字段是否有名为 tagName 的 tag
+func (ti *TypeInfo) HasTag(tagName string) bool {
+	_, ok := reflect.StructTag(ti.Tag).Lookup(tagName)
+	return ok
+}
+
+// ProtoName protobuf tag 中的字段名，优先 json= 部分，其次 name= 部分
+func (ti *TypeInfo) ProtoName() string {
+	name := ""
+	for _, opt := range strings.Split(reflect.StructTag(ti.Tag).Get("protobuf"), ",") {
+		switch {
+		case strings.HasPrefix(opt, "json="):
+			return strings.TrimPrefix(opt, "json=")
+		case strings.HasPrefix(opt, "name="):
+			name = strings.TrimPrefix(opt, "name=")
+		}
+	}
+	return name
+}
+
+// OneofName protobuf oneof 字段的名字，非 oneof 字段返回空
+func (ti *TypeInfo) OneofName() string {
+	return reflect.StructTag(ti.Tag).Get("protobuf_oneof")
+}
+
+// IsExported 字段是否导出
+func (ti *TypeInfo) IsExported() bool {
+	return ast.IsExported(ti.Name)
+}
+
+// TagOptions tag 中名字之后的部分，比如 thrift:"name,1,required" 返回 [1 required]
+func (ti *TypeInfo) TagOptions(tagName string) []string {
+	val := reflect.StructTag(ti.Tag).Get(tagName)
+	if val == "" {
+		return nil
+	}
+	return strings.Split(val, ",")[1:]
+}
+
+// TagName tag 中的名字部分，比如 m2s:"author,prefix=author." 返回 author
+func (ti *TypeInfo) TagName(tagName string) string {
+	return strings.Split(reflect.StructTag(ti.Tag).Get(tagName), ",")[0]
+}
+
+// TagOption tag 中的选项: name=value 返回 value，只有 name 时返回空，ok 表示选项是否存在；
+// 值为逗号时写作 sep=, 即可
+func (ti *TypeInfo) TagOption(tagName, name string) (value string, ok bool) {
+	opts := ti.TagOptions(tagName)
+	for idx, opt := range opts {
+		if opt == name {
+			return "", true
+		}
+		if strings.HasPrefix(opt, name+"=") {
+			value = strings.TrimPrefix(opt, name+"=")
+			if value == "" && idx+1 < len(opts) && opts[idx+1] == "" {
+				value = ","
+			}
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// IsRequired thrift 的 required 字段
+func (ti *TypeInfo) IsRequired() bool {
+	return utils.InStrings(ti.TagOptions("thrift"), "required")
+}
+
 func (ti *TypeInfo) IsBaseType() bool {
-	return utils.IsBaseType(ti.Type)
+	return ti.Kind != Array && utils.IsBaseType(ti.Type)
 }
 
 func (ti *TypeInfo) IsObjectType() bool {
@@ -0,0 +1,119 @@
+package tpl
+
+const MapToStructCSVTemplate = `
+{{- $model := printf "%s.%s" .ModelPkg .ModelName }}
+{{- $reader := .CSVReader }}
+{{- $writer := .CSVWriter }}
+
+// {{$reader}} 逐行读取 CSV，第一行为表头（map 的 key），空值视为不存在
+type {{$reader}} struct {
+	r      *csv.Reader
+	header []string
+	src    {{.ParamType}}
+	record int
+}
+
+// {{.CSVNewReader}} 读取表头，返回的 reader 不会把整个文件读入内存
+func {{.CSVNewReader}}(r io.Reader) (*{{$reader}}, error) {
+	cr := csv.NewReader(r)
+	cr.ReuseRecord = true
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	return &{{$reader}}{
+		r:      cr,
+		header: append([]string(nil), header...),
+		src:    make({{.ParamType}}, len(header)),
+		record: 1,
+	}, nil
+}
+
+// Read 读取下一条记录，读完返回 io.EOF；转换失败返回 *m2s.CSVError
+func (r *{{$reader}}) Read() (*{{$model}}, error) {
+	record, err := r.r.Read()
+	if err != nil {
+		return nil, err
+	}
+	r.record++
+	for idx, key := range r.header {
+		if record[idx] == "" {
+			delete(r.src, key)
+		} else {
+			r.src[key] = record[idx]
+		}
+	}
+	{{.TestResults}} := {{.FuncName}}(r.src{{.TestArgs}})
+	if err != nil {
+		return nil, m2s.NewCSVError(r.record, r.header, err)
+	}
+	return obj, nil
+}
+
+// {{$writer}} 写入表头和记录
+type {{$writer}} struct {
+	w      *csv.Writer
+	record []string
+}
+
+// {{.CSVHeader}} writer 写入的表头
+var {{.CSVHeader}} = []string{
+	{{- range .CSVFields }}
+	{{ printf "%q," .JsonName }}
+	{{- end }}
+}
+
+// {{.CSVNewWriter}} 写入表头
+func {{.CSVNewWriter}}(w io.Writer) (*{{$writer}}, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write({{.CSVHeader}}); err != nil {
+		return nil, err
+	}
+	return &{{$writer}}{w: cw, record: make([]string, len({{.CSVHeader}}))}, nil
+}
+
+// Write 写入一条记录，nil 指针写入空值
+func (w *{{$writer}}) Write(obj *{{$model}}) error {
+	{{- if .CSVJoin }}
+	var parts []string
+	{{- end }}
+	{{- range $idx, $fd := .CSVFields }}
+	{{- if .Sep }}
+	parts = parts[:0]
+	{{ printf "for _, v := range obj.%s {" .FieldName }}
+		{{- if eq .FieldType "string" }}
+		parts = append(parts, v)
+		{{- else if .EnumBase }}
+		{{ printf "parts = append(parts, fmt.Sprint(%s(v)))" .EnumBase }}
+		{{- else }}
+		parts = append(parts, fmt.Sprint(v))
+		{{- end }}
+	}
+	{{ printf "w.record[%d] = strings.Join(parts, %q)" $idx .Sep }}
+	{{- else if and .IsPointer .EnumBase }}
+	{{ printf "w.record[%d] = \"\"" $idx }}
+	{{ printf "if obj.%s != nil {" .FieldName }}
+		{{ printf "w.record[%d] = fmt.Sprint(%s(*obj.%s))" $idx .EnumBase .FieldName }}
+	}
+	{{- else if .IsPointer }}
+	{{ printf "w.record[%d] = \"\"" $idx }}
+	{{ printf "if obj.%s != nil {" .FieldName }}
+		{{ printf "w.record[%d] = fmt.Sprint(*obj.%s)" $idx .FieldName }}
+	}
+	{{- else if eq .FieldType "string" }}
+	{{ printf "w.record[%d] = obj.%s" $idx .FieldName }}
+	{{- else if .EnumBase }}
+	{{ printf "w.record[%d] = fmt.Sprint(%s(obj.%s))" $idx .EnumBase .FieldName }}
+	{{- else }}
+	{{ printf "w.record[%d] = fmt.Sprint(obj.%s)" $idx .FieldName }}
+	{{- end }}
+	{{- end }}
+	return w.w.Write(w.record)
+}
+
+// Flush 写入缓冲的数据
+func (w *{{$writer}}) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+`
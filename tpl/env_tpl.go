@@ -0,0 +1,103 @@
+package tpl
+
+type EnvFieldItem struct {
+	*FieldItem
+	Index      int    // 在 EnvVars 中的下标
+	Name       string // 环境变量名，不含前缀
+	Type       string // 字段类型，用于打印变量列表
+	Default    string
+	Required   bool
+	AllowEmpty bool // 空字符串作为有效值，默认视为未设置
+}
+
+// EnvAlloc 需要分配的嵌套指针结构体
+type EnvAlloc struct {
+	FieldName  string
+	NewExpr    string // 创建对象的表达式，比如: model.NewServerConfig()
+	InitMethod string
+}
+
+type EnvTemplateData struct {
+	FuncName   string // 加载函数，比如: genLoadAppConfigFromEnv
+	VarsName   string // 环境变量列表函数，比如: genAppConfigEnvVars
+	ModelPkg   string
+	ModelName  string
+	NewExpr    string
+	InitMethod string
+
+	Allocs      []*EnvAlloc
+	Fields      []*EnvFieldItem
+	OtherFields []*EnvFieldItem // 需要手动处理的字段
+}
+
+const EnvTemplate = `
+
+// {{.VarsName}} {{.ModelPkg}}.{{.ModelName}} 读取的环境变量
+func {{.VarsName}}(prefix string) []m2s.EnvVar {
+	return []m2s.EnvVar{
+		{{- range .Fields }}
+		{{ printf "{Name: m2s.EnvName(prefix, %q), Field: %q, Type: %q" .Name .FieldName .Type }}
+			{{- if .Default }}{{ printf ", Default: %q" .Default }}{{ end }}
+			{{- if .Required }}, Required: true{{ end }}
+			{{- if .AllowEmpty }}, AllowEmpty: true{{ end }}},
+		{{- end }}
+	}
+}
+
+func {{.FuncName}}(prefix string) (obj *{{.ModelPkg}}.{{.ModelName}}, err error) {
+	vars := {{.VarsName}}(prefix)
+	if err = m2s.CheckEnv(vars); err != nil {
+		return nil, err
+	}
+	obj = {{.NewExpr}}
+	{{- if .InitMethod }}
+	obj.{{.InitMethod}}()
+	{{- end }}
+	{{- range .Allocs }}
+	if obj.{{.FieldName}} == nil {
+		obj.{{.FieldName}} = {{.NewExpr}}
+		{{- if .InitMethod }}
+		obj.{{.FieldName}}.{{.InitMethod}}()
+		{{- end }}
+	}
+	{{- end }}
+	{{- range .Fields }}
+	if tmp, ok := vars[{{.Index}}].Lookup(); ok {
+		{{- if eq .GenType "direct" }}
+			{{- if .AssignExpr }}
+		{{ printf "if obj.%s, err = %sE(tmp%s); err != nil {" .FieldName .AssignExpr .AssignArgs }}
+			{{ printf "return nil, &m2s.KeyError{Key: vars[%d].Name, Err: err}" .Index }}
+		}
+			{{- else }}
+		{{ printf "obj.%s = tmp" .FieldName }}
+			{{- end }}
+		{{- else }}
+			{{- if .AssignExpr }}
+		{{ if .TypeConv }}num{{ else }}val{{ end }}{{ printf ", err := %sE(tmp%s)" .AssignExpr .AssignArgs }}
+		if err != nil {
+			{{ printf "return nil, &m2s.KeyError{Key: vars[%d].Name, Err: err}" .Index }}
+		}
+				{{- if .TypeConv }}
+		{{ printf "val := (%s)(num)" .TypeConv }}
+				{{- end }}
+			{{- else }}
+		val := tmp
+			{{- end }}
+			{{- if .IsPointer }}
+		{{ printf "obj.%s = &val" .FieldName }}
+			{{- else }}
+		{{ printf "obj.%s = val" .FieldName }}
+			{{- end }}
+		{{- end }}
+	}
+	{{- end }}
+	{{- if .OtherFields }}
+
+	// 需要手动处理的字段
+	{{- range .OtherFields }}
+	{{ printf "// obj.%s = ? (%s)" .FieldName .Name }}
+	{{- end }}
+	{{- end }}
+	return obj, nil
+}
+`
@@ -0,0 +1,150 @@
+package tpl
+
+type TestCase struct {
+	Name    string
+	Value   string // src 中的取值（Go 字面量）
+	Check   string // 校验语句，为空不校验
+	WantErr bool
+}
+
+const MapToStructTestPrefix = `
+// Auto generated code, DO NOT EDIT.
+
+//go:build go1.18
+// +build go1.18
+
+package {{.Package}}
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"testing"
+	{{- range .Imports }}
+	{{ printf "%q" . }}
+	{{- end }}
+)
+`
+
+const MapToStructTestTemplate = `
+{{- $model := printf "%s.%s" .ModelPkg .ModelName }}
+
+func {{.TestSrcName}}() {{.ParamType}} {
+	return {{.ParamType}}{
+		{{- range .Fields }}
+			{{- if .TestValue }}
+				{{ printf "%q: %s," .JsonName .TestValue }}
+			{{- end }}
+		{{- end }}
+	}
+}
+
+func {{.TestSrcName}}With(key string, val {{.ValueType}}) {{.ParamType}} {
+	src := {{.TestSrcName}}()
+	src[key] = val
+	return src
+}
+
+func Test{{.TestName}}(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     {{.ParamType}}
+		wantErr bool
+		check   func(t *testing.T, obj *{{$model}})
+	}{
+		{
+			name: "valid",
+			src:  {{.TestSrcName}}(),
+			check: func(t *testing.T, obj *{{$model}}) {
+				{{- range .Fields }}
+					{{- if .TestCheck }}
+						{{ .TestCheck }}
+					{{- end }}
+				{{- end }}
+			},
+		},
+		{
+			name: "missing",
+			src:  {{.ParamType}}{},
+			{{- if .RequiredKeys }}
+			wantErr: true,
+			{{- else }}
+			check: func(t *testing.T, obj *{{$model}}) {
+				want := {{.NewExpr}}
+				{{- if .InitMethod }}
+				want.{{.InitMethod}}()
+				{{- end }}
+				if !reflect.DeepEqual(obj, want) {
+					t.Errorf("obj = %+v, want %+v", obj, want)
+				}
+			},
+			{{- end }}
+		},
+		{{- range .Fields }}
+			{{- $field := . }}
+			{{- range .TestCases }}
+		{
+			name: {{ printf "%q" .Name }},
+			src:  {{ printf "%sWith(%q, %s)" $.TestSrcName $field.JsonName .Value }},
+			{{- if .WantErr }}
+			wantErr: true,
+			{{- end }}
+			{{- if .Check }}
+			check: func(t *testing.T, obj *{{$model}}) {
+				{{ .Check }}
+			},
+			{{- end }}
+		},
+			{{- end }}
+		{{- end }}
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			{{.TestResults}} := {{.FuncName}}(c.src{{.TestArgs}})
+			if (err != nil) != c.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, c.wantErr)
+			}
+			if err == nil && c.check != nil {
+				c.check(t, obj)
+			}
+		})
+	}
+}
+
+func Benchmark{{.TestName}}(b *testing.B) {
+	src := {{.TestSrcName}}()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		{{.FuzzResults}} = {{.FuncName}}(src{{.TestArgs}})
+	}
+}
+{{- if or (eq .ValueType "string") (eq .ValueType "interface{}") }}
+
+// Fuzz{{.TestName}} 生成的代码对任意输入都不能 panic
+func Fuzz{{.TestName}}(f *testing.F) {
+	keys := []string{
+		{{- range .KnownKeys }}
+			{{ printf "%q," . }}
+		{{- end }}
+	}
+	f.Add("42", int64(42), 1.5, true)
+	f.Add("", int64(-1), -0.5, false)
+	f.Fuzz(func(t *testing.T, str string, num int64, flt float64, flag bool) {
+		{{- if eq .ValueType "string" }}
+		vals := []string{str, strconv.FormatInt(num, 10), strconv.FormatFloat(flt, 'g', -1, 64), strconv.FormatBool(flag)}
+		{{- else }}
+		vals := []interface{}{str, num, flt, flag, json.Number(str), nil}
+		{{- end }}
+		src := {{.ParamType}}{str: vals[0]}
+		for idx, key := range keys {
+			src[key] = vals[(idx+int(uint8(num)))%len(vals)]
+		}
+		{{.FuzzResults}} = {{.FuncName}}(src{{.TestArgs}})
+	})
+}
+{{- end }}
+`
@@ -8,21 +8,94 @@ type FieldItem struct {
 	FieldName  string
 	JsonName   string
 	TypeConv   string // 类型转换
+	EnumBase   string // 枚举的底层类型，写入 CSV 时先转换为底层类型，不使用 String()
 	AssignExpr string // 赋值表达式
+	AssignArgs string // 赋值表达式的额外参数，比如 proto 枚举的 ", model.Xxx_value"
+	FieldConst string // 字段标识常量，presence 模式下使用
+	Lookup     string // 从 map 取值的 if 语句，取到的值为 tmp
+	KeyExpr    string // key 的表达式，比如: "author.name", prefix + "title"
+	Alloc      string // 赋值前分配为 nil 的父结构体，比如: obj.Author = &model.Author{}
+
+	ElemType   string     // 带下标的切片: 元素类型
+	ElemPrefix string     // 带下标的切片: key 前缀的表达式，比如: "items."
+	ElemSep    string     // 带下标的切片: 下标和元素字段之间的分隔符
+	ElemApply  string     // 带下标的切片: 结构体元素的 Apply 函数
+	ElemInit   string     // 带下标的切片: 初始化为 nil 的指针元素的语句
+	ElemNew    string     // 带下标的切片: 追加新元素的语句（使用构造函数或 Default），为空时追加零值
+	ElemRef    string     // 带下标的切片: 传给 Apply 函数的元素指针，比如: &obj.Items[i]
+	ElemKey    string     // 带下标的切片: 判断下标之后的 key 是否为结构体元素字段的函数
+	Elem       *FieldItem // 带下标的切片: 基本类型元素的赋值方式
+	Sep        string     // 分隔符拼接的切片: m2s:"ids,sep=|"
+
+	WrapperType  string // proto oneof 的包装类型
+	WrapperField string // proto oneof 包装类型中的字段
+
+	TestValue string      // 单测: 正常取值（Go 字面量）
+	TestCheck string      // 单测: 正常取值的校验语句
+	TestCases []*TestCase // 单测: 错误类型、边界值等用例
 }
 
 type MapToStructTemplateData struct {
-	FuncName  string
-	Package   string
-	ParamName string
-	ParamType string
-	ModelPkg  string
-	ModelName string
-
-	EnumFields   []*FieldItem // 枚举类型
-	DirectFields []*FieldItem // 类型相同
-	AssignFields []*FieldItem // 带赋值表达式的，比如：指针类型
-	OtherFields  []*FieldItem // 其他不能处理的类型
+	FuncName   string
+	ApplyName  string // 在已有对象上赋值的函数
+	BaseName   string // 去掉 MapTo 前缀的函数名
+	Package    string
+	Imports    []string // 额外 import 的包，比如 -type 模式下类型所在的包
+	ParamName  string
+	ParamType  string
+	ModelPkg   string
+	ModelName  string
+	Strict     bool   // 严格模式：转换失败返回 error
+	MaxIndex   int    // 带下标的 key 的下标上限（不含）
+	NewExpr    string // 创建对象的表达式，比如: &model.Xxx{}, model.NewXxx()
+	InitMethod string // 创建对象后调用的初始化方法，比如: Default
+
+	RequiredKeys []string // 严格模式下必须存在的 key（thrift required）
+
+	Presence   bool         // 返回 src 中存在的字段集合
+	FieldEnum  string       // 字段标识类型
+	FieldsType string       // 字段集合(bitset)类型
+	FieldWords int          // bitset 长度
+	Fields     []*FieldItem // 可处理的字段，按结构体定义顺序
+
+	Unknown   string   // 未知 key 的处理方式: return/callback/error
+	KnownKeys []string // 结构体所有字段对应的 key
+
+	ExtraParams  string // 额外的入参，比如: ", onUnknown func(key string)"
+	ExtraArgs    string // 额外入参的调用参数
+	ExtraResults string // 额外的返回值定义，比如: "fields xxFields, "
+	ExtraValues  string // 额外的返回值
+
+	ApplyOnly    bool                       // 只生成 Apply 函数（合并多个 src 时）
+	SkipTypes    bool                       // 不生成 presence 类型（合并多个 src 时已生成）
+	Slice        bool                       // 生成批量转换函数
+	CSVFields    []*FieldItem               // 写入 CSV 的字段
+	CSVJoin      bool                       // CSV 中有分隔符拼接的切片字段
+	CSVReader    string                     // CSV reader 类型，比如: genBookCSVReader
+	CSVWriter    string                     // CSV writer 类型
+	CSVHeader    string                     // CSV writer 写入的表头
+	CSVNewReader string                     // 创建 CSV reader 的函数，比如: genNewBookCSVReader
+	CSVNewWriter string                     // 创建 CSV writer 的函数
+	Register     bool                       // 在 init 中注册到 m2s
+	ApplyResults string                     // 注册: 接收 Apply 的返回值, 比如 "_, err"
+	Elems        []*MapToStructTemplateData // 带下标的切片中结构体元素的 Apply 函数
+	ElemKeyFunc  string                     // 结构体元素: 判断 key 是否为元素字段的函数
+
+	ValueType   string // map 的 value 类型
+	TestName    string // 单测函数名后缀
+	TestSrcName string // 单测: 生成正常取值 map 的函数
+	TestArgs    string // 单测: 调用时的额外参数
+	TestResults string // 单测: 接收返回值, 比如 "obj, _, err"
+	FuzzResults string // 单测: 忽略所有返回值
+
+	EnumFields    []*FieldItem // 枚举类型
+	DirectFields  []*FieldItem // 类型相同
+	AssignFields  []*FieldItem // 带赋值表达式的，比如：指针类型
+	OneofFields   []*FieldItem // proto oneof 字段
+	SliceFields   []*FieldItem // 多值 map 的切片字段
+	IndexedFields []*FieldItem // 带下标的 key 组成的切片，比如: items.0.title
+	JSONFields    []*FieldItem // 值为 JSON 字符串的字段: m2s:"tags,json"
+	OtherFields   []*FieldItem // 其他不能处理的类型
 }
 
 const MapToStructPrefix = `
@@ -31,25 +104,158 @@ const MapToStructPrefix = `
 package {{.Package}}
 
 import (
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/adyzng/gotool/m2s"
 	"github.com/spf13/cast"
+	{{- range .Imports }}
+	{{ printf "%q" . }}
+	{{- end }}
 )
 `
 
 const MapToStructTemplate = `
+{{- if and .Presence (not .SkipTypes) }}
+
+// {{.FieldEnum}} 标识 {{.ModelPkg}}.{{.ModelName}} 的字段
+type {{.FieldEnum}} uint
+
+const (
+	{{- range $idx, $fd := .Fields }}
+		{{- if eq $idx 0 }}
+			{{ printf "%s %s = iota" .FieldConst $.FieldEnum }}
+		{{- else }}
+			{{ .FieldConst }}
+		{{- end }}
+	{{- end }}
+)
+
+var {{.FieldEnum}}Names = [...]string{
+	{{- range .Fields }}
+		{{ printf "%q," .FieldName }}
+	{{- end }}
+}
+
+func (f {{.FieldEnum}}) String() string {
+	return {{.FieldEnum}}Names[f]
+}
+
+// {{.FieldsType}} 记录 {{.ParamName}} 中存在的字段
+type {{.FieldsType}} [{{.FieldWords}}]uint64
+
+func (fs *{{.FieldsType}}) set(f {{.FieldEnum}}) {
+	fs[f/64] |= 1 << (f % 64)
+}
+
+// Has 字段是否出现在 {{.ParamName}} 中
+func (fs {{.FieldsType}}) Has(f {{.FieldEnum}}) bool {
+	return fs[f/64]&(1<<(f%64)) != 0
+}
+
+// Names 出现在 {{.ParamName}} 中的字段名
+func (fs {{.FieldsType}}) Names() []string {
+	names := make([]string, 0, len({{.FieldEnum}}Names))
+	for idx, name := range {{.FieldEnum}}Names {
+		if fs.Has({{.FieldEnum}}(idx)) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+{{- end }}
 
-func {{.FuncName}}({{.ParamName}} {{.ParamType}}) (obj *{{.ModelPkg}}.{{.ModelName}}, err error) {
-	obj = &{{.ModelPkg}}.{{.ModelName}}{}
-	{{$mapParam := .ParamName -}}
+{{- if not .ApplyOnly }}
 
-	{{ $len1 := len .DirectFields }}
+func {{.FuncName}}({{.ParamName}} {{.ParamType}}{{.ExtraParams}}) (obj *{{.ModelPkg}}.{{.ModelName}}, {{.ExtraResults}}err error) {
+	{{- range .RequiredKeys }}
+	if _, ok := {{$.ParamName}}[{{ printf "%q" . }}]; !ok {
+		return nil, {{$.ExtraValues}}&m2s.KeyError{Key: {{ printf "%q" . }}, Err: m2s.ErrMissingKey}
+	}
+	{{- end }}
+	obj = {{.NewExpr}}
+	{{- if .InitMethod }}
+	obj.{{.InitMethod}}()
+	{{- end }}
+	if {{.ExtraValues}}err = {{.ApplyName}}({{.ParamName}}, obj{{.ExtraArgs}}); err != nil {
+		return nil, {{.ExtraValues}}err
+	}
+	return obj, {{.ExtraValues}}nil
+}
+{{- end }}
+
+// {{.ApplyName}} 只覆盖 {{.ParamName}} 中存在的字段
+func {{.ApplyName}}({{.ParamName}} {{.ParamType}}, obj *{{.ModelPkg}}.{{.ModelName}}{{.ExtraParams}}) ({{.ExtraResults}}err error) {
+	{{- $strict := .Strict -}}
+	{{- $presence := .Presence -}}
+	{{- $ret := .ExtraValues -}}
+	{{- if .Unknown }}
+	{{ print "// 检查未知的 key" }}
+	{{- if eq .Unknown "error" }}
+	var unknown []string
+	{{- end }}
+	for key := range {{.ParamName}} {
+		switch key {
+		{{- if .KnownKeys }}
+		case {{ range $idx, $key := .KnownKeys }}{{ if $idx }}, {{ end }}{{ printf "%q" $key }}{{ end }}:
+		{{- end }}
+		default:
+			{{- range .IndexedFields }}
+			{{- if $.Strict }}
+			if _, ok := m2s.KeyIndex(key, {{ .ElemPrefix }}, {{ printf "%q" .ElemSep }}, {{ or .ElemKey "nil" }}); ok {
+			{{- else }}
+			if idx, ok := m2s.KeyIndex(key, {{ .ElemPrefix }}, {{ printf "%q" .ElemSep }}, {{ or .ElemKey "nil" }}); ok && idx < {{ $.MaxIndex }} {
+			{{- end }}
+				continue
+			}
+			{{- end }}
+			{{- if eq .Unknown "callback" }}
+			if onUnknown != nil {
+				onUnknown(key)
+			}
+			{{- else }}
+			unknown = append(unknown, key)
+			{{- end }}
+		}
+	}
+	{{- if ne .Unknown "callback" }}
+	sort.Strings(unknown)
+	{{- end }}
+	{{- if eq .Unknown "error" }}
+	if len(unknown) > 0 {
+		return {{ $ret }}&m2s.UnknownKeyError{Keys: unknown}
+	}
+	{{- end }}
+	{{ end -}}
+
+	{{ $len1 := len .DirectFields -}}
 	{{ if gt $len1 0}}
 	{{ print "// 直接赋值的字段" }}
 	{{- range .DirectFields }}
-		{{- if .AssignExpr }}
-			{{ printf "obj.%s = %s(%s[\"%s\"])" .FieldName .AssignExpr $mapParam .JsonName }}
+		{{ .Lookup }}
+		{{- if .Alloc }}
+			{{ .Alloc }}
+		{{- end }}
+		{{- if and .AssignExpr $strict }}
+			{{ printf "	if obj.%s, err = %sE(tmp%s); err != nil {" .FieldName .AssignExpr .AssignArgs }}
+			{{ printf "		return %s&m2s.KeyError{Key: %s, Err: err}" $ret .KeyExpr }}
+			{{ print "	}" }}
+		{{- else if .AssignExpr }}
+			{{ printf "	obj.%s = %s(tmp%s)" .FieldName .AssignExpr .AssignArgs }}
 		{{- else }}
-			{{ printf "obj.%s = %s[\"%s\"]" .FieldName $mapParam .JsonName }}
+			{{ printf "	obj.%s = tmp" .FieldName }}
 		{{- end }}
+		{{- if $presence }}
+			{{ printf "	fields.set(%s)" .FieldConst }}
+		{{- end }}
+		{{ print "}" }}
 	{{- end }}
 	{{- end -}}
 
@@ -57,21 +263,29 @@ func {{.FuncName}}({{.ParamName}} {{.ParamType}}) (obj *{{.ModelPkg}}.{{.ModelNa
 	{{ if gt $len2 0}}
 		{{ print "// 枚举类型" }}
 		{{- range .EnumFields }}
-			{{ printf "if tmp, ok := %s[\"%s\"]; ok {" $mapParam .JsonName }}
-				{{- if .AssignExpr }}
-					{{ printf "	val := (%s)(%s(tmp))" .TypeConv .AssignExpr }}
-					{{- if .IsPointer }}
-						{{ printf "	obj.%s = &val" .FieldName }}
-					{{- else }}
-						{{ printf "	obj.%s = val" .FieldName }}
-					{{- end }}
+			{{ .Lookup }}
+			{{- if .Alloc }}
+				{{ .Alloc }}
+			{{- end }}
+				{{- if and .AssignExpr $strict }}
+					{{ printf "	num, err := %sE(tmp%s)" .AssignExpr .AssignArgs }}
+					{{ print "	if err != nil {" }}
+					{{ printf "		return %s&m2s.KeyError{Key: %s, Err: err}" $ret .KeyExpr }}
+					{{ print "	}" }}
+					{{ printf "	val := (%s)(num)" .TypeConv }}
+				{{- else if .AssignExpr }}
+					{{ printf "	val := (%s)(%s(tmp%s))" .TypeConv .AssignExpr .AssignArgs }}
+				{{- else }}
+					{{ print "	val := tmp" }}
+				{{- end }}
+				{{- if .IsPointer }}
+					{{ printf "	obj.%s = &val" .FieldName }}
 				{{- else }}
-					{{- if .IsPointer }}
-						{{ printf "	obj.%s = &tmp" .FieldName }}
-					{{- else }}
-						{{ printf "	obj.%s = tmp" .FieldName }}
-					{{- end }}
+					{{ printf "	obj.%s = val" .FieldName }}
 				{{- end }}
+			{{- if $presence }}
+				{{ printf "	fields.set(%s)" .FieldConst }}
+			{{- end }}
 			{{ print "}" }}
 		{{- end }}
 	{{- end -}}
@@ -80,25 +294,205 @@ func {{.FuncName}}({{.ParamName}} {{.ParamType}}) (obj *{{.ModelPkg}}.{{.ModelNa
 	{{ if gt $len2 0}}
 	{{ print "// 带赋值表达式的（指针类型）" }}
 	{{- range .AssignFields }}
-		{{ printf "if tmp, ok := %s[\"%s\"]; ok {" $mapParam .JsonName }}
-			{{- if .AssignExpr }}
-				{{ printf "	val := %s(tmp)" .AssignExpr }}
-				{{- if .IsPointer }}
-					{{ printf "	obj.%s = &val" .FieldName }}
+		{{ .Lookup }}
+		{{- if .Alloc }}
+			{{ .Alloc }}
+		{{- end }}
+			{{- if and .AssignExpr $strict }}
+				{{ printf "	val, err := %sE(tmp%s)" .AssignExpr .AssignArgs }}
+				{{ print "	if err != nil {" }}
+				{{ printf "		return %s&m2s.KeyError{Key: %s, Err: err}" $ret .KeyExpr }}
+				{{ print "	}" }}
+			{{- else if .AssignExpr }}
+				{{ printf "	val := %s(tmp%s)" .AssignExpr .AssignArgs }}
+			{{- else }}
+				{{ print "	val := tmp" }}
+			{{- end }}
+			{{- if .IsPointer }}
+				{{ printf "	obj.%s = &val" .FieldName }}
+			{{- else }}
+				{{ printf "	obj.%s = val" .FieldName }}
+			{{- end }}
+		{{- if $presence }}
+			{{ printf "	fields.set(%s)" .FieldConst }}
+		{{- end }}
+		{{ print "}" }}
+	{{- end }}
+	{{- end -}}
+
+	{{ $len4 := len .OneofFields }}
+	{{ if gt $len4 0}}
+	{{ print "// proto oneof 字段" }}
+	{{- range .OneofFields }}
+		{{ .Lookup }}
+			{{- if and .AssignExpr $strict }}
+				{{- if .TypeConv }}
+					{{ printf "	num, err := %sE(tmp%s)" .AssignExpr .AssignArgs }}
 				{{- else }}
-					{{ printf "	obj.%s = val" .FieldName }}
+					{{ printf "	val, err := %sE(tmp%s)" .AssignExpr .AssignArgs }}
 				{{- end }}
+				{{ print "	if err != nil {" }}
+				{{ printf "		return %s&m2s.KeyError{Key: %s, Err: err}" $ret .KeyExpr }}
+				{{ print "	}" }}
+				{{- if .TypeConv }}
+					{{ printf "	val := (%s)(num)" .TypeConv }}
+				{{- end }}
+			{{- else if and .AssignExpr .TypeConv }}
+				{{ printf "	val := (%s)(%s(tmp%s))" .TypeConv .AssignExpr .AssignArgs }}
+			{{- else if .AssignExpr }}
+				{{ printf "	val := %s(tmp%s)" .AssignExpr .AssignArgs }}
 			{{- else }}
-				{{- if .IsPointer }}
-					{{ printf "	obj.%s = &tmp" .FieldName }}
+				{{ print "	val := tmp" }}
+			{{- end }}
+			{{- if .IsPointer }}
+				{{ printf "	obj.%s = &%s{%s: &val}" .FieldName .WrapperType .WrapperField }}
+			{{- else }}
+				{{ printf "	obj.%s = &%s{%s: val}" .FieldName .WrapperType .WrapperField }}
+			{{- end }}
+			{{- if $presence }}
+				{{ printf "	fields.set(%s)" .FieldConst }}
+			{{- end }}
+		{{ print "}" }}
+	{{- end }}
+	{{- end -}}
+
+	{{ $len5 := len .SliceFields }}
+	{{ if gt $len5 0}}
+	{{ print "// 切片字段，取所有的值或者按分隔符拆分" }}
+	{{- range .SliceFields }}
+		{{ .Lookup }}
+		{{- if .Alloc }}
+			{{ .Alloc }}
+		{{- end }}
+		{{- if and .Sep $strict }}
+			{{ printf "	items, err := m2s.SplitE(tmp, %q)" .Sep }}
+			{{ print "	if err != nil {" }}
+			{{ printf "		return %s&m2s.KeyError{Key: %s, Err: err}" $ret .KeyExpr }}
+			{{ print "	}" }}
+		{{- else if .Sep }}
+			{{ printf "	items := m2s.Split(tmp, %q)" .Sep }}
+		{{- end }}
+		{{- $items := "tmp" }}
+		{{- if .Sep }}
+			{{- $items = "items" }}
+		{{- end }}
+			{{ printf "	val := make([]%s, 0, len(%s))" .FieldType $items }}
+			{{- if and .AssignExpr $strict }}
+				{{ printf "	for idx, item := range %s {" $items }}
+				{{ printf "		v, err := %sE(item%s)" .AssignExpr .AssignArgs }}
+				{{ print "		if err != nil {" }}
+				{{ printf "			return %s&m2s.KeyError{Key: %s, Err: &m2s.ElemError{Index: idx, Err: err}}" $ret .KeyExpr }}
+				{{ print "		}" }}
+				{{- if .TypeConv }}
+					{{ printf "		val = append(val, %s(v))" .TypeConv }}
 				{{- else }}
-					{{ printf "	obj.%s = tmp" .FieldName }}
+					{{ print "		val = append(val, v)" }}
 				{{- end }}
+			{{- else if and .AssignExpr .TypeConv }}
+				{{ printf "	for _, item := range %s {" $items }}
+				{{ printf "		val = append(val, %s(%s(item%s)))" .TypeConv .AssignExpr .AssignArgs }}
+			{{- else if .AssignExpr }}
+				{{ printf "	for _, item := range %s {" $items }}
+				{{ printf "		val = append(val, %s(item%s))" .AssignExpr .AssignArgs }}
+			{{- else }}
+				{{ printf "	for _, item := range %s {" $items }}
+				{{ print "		val = append(val, item)" }}
+			{{- end }}
+			{{ print "	}" }}
+			{{ printf "	obj.%s = val" .FieldName }}
+			{{- if $presence }}
+				{{ printf "	fields.set(%s)" .FieldConst }}
 			{{- end }}
 		{{ print "}" }}
 	{{- end }}
 	{{- end -}}
 
+	{{ $len6 := len .IndexedFields }}
+	{{ if gt $len6 0}}
+	{{ print "// 带下标的 key 组成的切片，比如: items.0.title" }}
+	{{ printf "var indexLen [%d]int" $len6 }}
+	{{ printf "for key := range %s {" .ParamName }}
+	{{- range $idx, $fd := .IndexedFields }}
+		{{- if $strict }}
+		{{ printf "	if idx, ok := m2s.KeyIndex(key, %s, %q, %s); ok && idx >= %d {" .ElemPrefix .ElemSep (or .ElemKey "nil") $.MaxIndex }}
+		{{ printf "		return %s&m2s.KeyError{Key: key, Err: &m2s.ElemError{Index: idx, Err: m2s.ErrIndexRange}}" $ret }}
+		{{ printf "	} else if ok && idx >= indexLen[%d] {" $idx }}
+		{{- else }}
+		{{ printf "	if idx, ok := m2s.KeyIndex(key, %s, %q, %s); ok && idx < %d && idx >= indexLen[%d] {" .ElemPrefix .ElemSep (or .ElemKey "nil") $.MaxIndex $idx }}
+		{{- end }}
+		{{ printf "		indexLen[%d] = idx + 1" $idx }}
+		{{ print "	}" }}
+	{{- end }}
+	{{ print "}" }}
+	{{- range $idx, $fd := .IndexedFields }}
+		{{ printf "if n := indexLen[%d]; n > 0 {" $idx }}
+		{{- if .Alloc }}
+			{{ .Alloc }}
+		{{- end }}
+		{{ print "	// 保留已有的元素，只在下标超出时追加" }}
+		{{- if .ElemNew }}
+		{{ printf "	for len(obj.%s) < n {" .FieldName }}
+			{{ .ElemNew }}
+		{{ print "	}" }}
+		{{- else }}
+		{{ printf "	if n > len(obj.%s) {" .FieldName }}
+		{{ printf "		obj.%s = append(obj.%s, make([]%s, n-len(obj.%s))...)" .FieldName .FieldName .ElemType .FieldName }}
+		{{ print "	}" }}
+		{{- end }}
+		{{ print "	for i := 0; i < n; i++ {" }}
+		{{- if .ElemApply }}
+			{{- if .ElemInit }}
+				{{ .ElemInit }}
+			{{- end }}
+			{{ printf "	if err = %s(%s, %s, %s+strconv.Itoa(i)+%q); err != nil {" .ElemApply $.ParamName .ElemRef .ElemPrefix .ElemSep }}
+			{{ printf "		return %serr" $ret }}
+			{{ print "	}" }}
+		{{- else }}
+			{{ printf "	key := %s + strconv.Itoa(i)" .ElemPrefix }}
+			{{- with .Elem }}
+			{{ .Lookup }}
+			{{- if and .AssignExpr $strict }}
+				{{ printf "	if obj.%s, err = %sE(tmp%s); err != nil {" .FieldName .AssignExpr .AssignArgs }}
+				{{ printf "		return %s&m2s.KeyError{Key: %s, Err: err}" $ret .KeyExpr }}
+				{{ print "	}" }}
+			{{- else if .AssignExpr }}
+				{{ printf "	obj.%s = %s(tmp%s)" .FieldName .AssignExpr .AssignArgs }}
+			{{- else }}
+				{{ printf "	obj.%s = tmp" .FieldName }}
+			{{- end }}
+			{{ print "}" }}
+			{{- end }}
+		{{- end }}
+		{{ print "	}" }}
+		{{- if $presence }}
+			{{ printf "	fields.set(%s)" .FieldConst }}
+		{{- end }}
+		{{ print "}" }}
+	{{- end }}
+	{{- end -}}
+
+	{{ $len7 := len .JSONFields }}
+	{{ if gt $len7 0}}
+	{{ print "// 值为 JSON 字符串的字段" }}
+	{{- range .JSONFields }}
+		{{ .Lookup }}
+		{{- if .Alloc }}
+			{{ .Alloc }}
+		{{- end }}
+		{{- if $strict }}
+			{{ printf "	if err = m2s.UnmarshalJSON(tmp, &obj.%s); err != nil {" .FieldName }}
+			{{ printf "		return %s&m2s.KeyError{Key: %s, Err: err}" $ret .KeyExpr }}
+			{{ print "	}" }}
+		{{- else }}
+			{{ printf "	_ = m2s.UnmarshalJSON(tmp, &obj.%s)" .FieldName }}
+		{{- end }}
+		{{- if $presence }}
+			{{ printf "	fields.set(%s)" .FieldConst }}
+		{{- end }}
+		{{ print "}" }}
+	{{- end }}
+	{{- end -}}
+
 	{{ $len3 := len .OtherFields }}
 	{{ if gt $len3 0}}
 		{{ print "// 需要手动处理的字段" }}
@@ -107,6 +501,62 @@ func {{.FuncName}}({{.ParamName}} {{.ParamType}}) (obj *{{.ModelPkg}}.{{.ModelNa
 		{{- end }}
 	{{- end }}
 
-	return obj, err
+	return {{ $ret }}err
+}
+{{- if .ElemKeyFunc }}
+
+// {{.ElemKeyFunc}} 去掉元素前缀后的 key 是否为 {{.ModelPkg}}.{{.ModelName}} 的字段
+func {{.ElemKeyFunc}}(key string) bool {
+	{{- if .KnownKeys }}
+	switch key {
+	case {{ range $idx, $key := .KnownKeys }}{{ if $idx }}, {{ end }}{{ printf "%q" $key }}{{ end }}:
+		return true
+	}
+	{{- end }}
+	{{- range .IndexedFields }}
+	if _, ok := m2s.KeyIndex(key, {{ printf "%q" .JsonName }}, {{ printf "%q" .ElemSep }}, {{ or .ElemKey "nil" }}); ok {
+		return true
+	}
+	{{- end }}
+	return false
+}
+{{- end }}
+{{- if .Slice }}
+
+// {{.FuncName}}Slice 按顺序批量转换，失败时返回 *m2s.RowError
+func {{.FuncName}}Slice(srcs []{{.ParamType}}) ([]*{{.ModelPkg}}.{{.ModelName}}, error) {
+	return {{.FuncName}}SliceParallel(srcs, 1)
+}
+
+// {{.FuncName}}SliceParallel 最多 workers 个 goroutine 并发转换，结果顺序与 srcs 一致；workers <= 1 时顺序转换
+func {{.FuncName}}SliceParallel(srcs []{{.ParamType}}, workers int) ([]*{{.ModelPkg}}.{{.ModelName}}, error) {
+	list := make([]*{{.ModelPkg}}.{{.ModelName}}, len(srcs))
+	err := m2s.ForEach(len(srcs), workers, func(idx int) (err error) {
+		list[idx], {{.ApplyResults}} = {{.FuncName}}(srcs[idx]{{.TestArgs}})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+{{- end }}
+{{- if .Register }}
+
+func init() {
+	m2s.Register(({{.ParamType}})(nil), (*{{.ModelPkg}}.{{.ModelName}})(nil), &m2s.Converter{
+		MapTo: func(src interface{}) (interface{}, error) {
+			{{.TestResults}} := {{.FuncName}}(src.({{.ParamType}}){{.TestArgs}})
+			if err != nil {
+				return nil, err
+			}
+			return obj, nil
+		},
+		Apply: func(src interface{}, obj interface{}) error {
+			{{.ApplyResults}} := {{.ApplyName}}(src.({{.ParamType}}), obj.(*{{.ModelPkg}}.{{.ModelName}}){{.TestArgs}})
+			return err
+		},
+	})
 }
+{{- end }}
 `
@@ -25,6 +25,8 @@ func TestMap2StructTpl(t *testing.T) {
 
 	data := MapToStructTemplateData{
 		Package:   "test",
+		FuncName:  "genMapToApiBookItem",
+		ApplyName: "genApplyApiBookItem",
 		ParamName: "mp",
 		ParamType: "map[string]string",
 		ModelPkg:  "model",
@@ -0,0 +1,106 @@
+package tpl
+
+// MergeSource 合并时的一个 src 参数
+type MergeSource struct {
+	Name      string
+	ParamType string
+	ApplyName string
+	Results   string   // 接收 Apply 的返回值，比如: "fields0, err :="
+	FieldsVar string   // presence 模式下 Apply 返回的字段集合
+	Unknown   string   // -unknown=return 时 Apply 返回的未知 key
+	Higher    []string // 优先级更高的参数，这些参数中存在的 key 不从当前参数转换
+	Own       string   // 去掉 Higher 中存在的 key 之后的 map，Higher 为空时不需要
+}
+
+type MergeTemplateData struct {
+	FuncName   string
+	ModelPkg   string
+	ModelName  string
+	NewExpr    string
+	InitMethod string
+
+	Sources        []*MergeSource // 按优先级从低到高
+	RequiredChecks []string       // 所有 src 中都不存在时返回错误的语句
+
+	Presence    bool
+	FieldEnum   string
+	FieldsType  string
+	SourcesType string // 记录字段来自哪个 src
+	FieldCount  int
+
+	Unknown      string
+	ExtraParams  string
+	ExtraArgs    string
+	ExtraResults string
+	ExtraValues  string
+}
+
+const MapToStructMergeTemplate = `
+{{- $model := printf "%s.%s" .ModelPkg .ModelName }}
+{{- if .Presence }}
+
+// {{.SourcesType}} 记录每个字段的值来自第几个参数（从 0 开始），-1 表示都不存在
+type {{.SourcesType}} [{{.FieldCount}}]int8
+
+// From 字段的值来自第几个参数，-1 表示都不存在
+func (s {{.SourcesType}}) From(f {{.FieldEnum}}) int {
+	return int(s[f])
+}
+
+func (s *{{.SourcesType}}) merge(fields *{{.FieldsType}}, from {{.FieldsType}}, idx int8) {
+	for f := range s {
+		if from.Has({{.FieldEnum}}(f)) {
+			s[f] = idx
+			fields.set({{.FieldEnum}}(f))
+		}
+	}
+}
+{{- end }}
+
+// {{.FuncName}} 每个 key 只从存在该 key 的优先级最高（最后）的参数转换
+func {{.FuncName}}(
+	{{- range $idx, $src := .Sources }}{{ if $idx }}, {{ end }}{{ .Name }} {{ .ParamType }}{{ end }}
+	{{- .ExtraParams }}) (obj *{{$model}}, {{.ExtraResults}}err error) {
+	{{- range .RequiredChecks }}
+	{{ . }}
+	{{- end }}
+	obj = {{.NewExpr}}
+	{{- if .InitMethod }}
+	obj.{{.InitMethod}}()
+	{{- end }}
+	{{- if .Presence }}
+	for idx := range sources {
+		sources[idx] = -1
+	}
+	{{- end }}
+	{{- range $idx, $src := .Sources }}
+	{{- if .Own }}
+	{{ .Own }} := make({{ .ParamType }}, len({{ .Name }}))
+	for key, val := range {{ .Name }} {
+		{{- range .Higher }}
+		if _, ok := {{ . }}[key]; ok {
+			continue
+		}
+		{{- end }}
+		{{ .Own }}[key] = val
+	}
+	{{ .Results }} {{ .ApplyName }}({{ .Own }}, obj{{ $.ExtraArgs }})
+	{{- else }}
+	{{ .Results }} {{ .ApplyName }}({{ .Name }}, obj{{ $.ExtraArgs }})
+	{{- end }}
+	if err != nil {
+		return nil, {{ $.ExtraValues }}err
+	}
+	{{- if $.Presence }}
+	sources.merge(&fields, {{ .FieldsVar }}, {{ $idx }})
+	{{- end }}
+	{{- if eq $.Unknown "return" }}
+	unknown = append(unknown, {{ .Unknown }}...)
+	{{- end }}
+	{{- end }}
+	{{- if eq .Unknown "return" }}
+	unknown = m2s.SortKeys(unknown)
+	{{- end }}
+	return obj, {{.ExtraValues}}nil
+}
+`
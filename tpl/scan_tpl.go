@@ -0,0 +1,157 @@
+package tpl
+
+type ScanTemplateData struct {
+	ModelPkg   string
+	ModelName  string
+	NewExpr    string
+	InitMethod string
+
+	ColumnsName string // 按字段顺序的列名，比如: genBookColumns
+	IndexName   string // 列名对应的字段下标
+	SetName     string // 按字段下标赋值
+	RowsName    string // 扫描 *sql.Rows 的函数，为空不生成
+	RowName     string // 扫描 *sql.Row 的函数，为空不生成
+	Helpers     bool   // 生成 Columns/Index/Set，同一个结构体只生成一次
+
+	Fields      []*FieldItem // 可处理的字段，下标即字段编号
+	OtherFields []*FieldItem // 需要手动处理的字段
+}
+
+const ScanTemplate = `
+{{- $model := printf "%s.%s" .ModelPkg .ModelName }}
+{{- if .Helpers }}
+
+// {{.ColumnsName}} {{$model}} 对应的列，按字段顺序
+var {{.ColumnsName}} = []string{
+	{{- range .Fields }}
+	{{ printf "%q," .JsonName }}
+	{{- end }}
+}
+
+// {{.IndexName}} 列对应的字段编号，未知的列返回 -1
+func {{.IndexName}}(column string) int {
+	switch column {
+	{{- range $idx, $fd := .Fields }}
+	{{ printf "case %q:" .JsonName }}
+		return {{ $idx }}
+	{{- end }}
+	}
+	return -1
+}
+
+// {{.SetName}} 把列的值赋给字段，NULL 不赋值
+func {{.SetName}}(obj *{{$model}}, field int, tmp interface{}) (err error) {
+	if tmp == nil {
+		return nil
+	}
+	if b, ok := tmp.([]byte); ok {
+		tmp = string(b)
+	}
+	switch field {
+	{{- range $idx, $fd := .Fields }}
+	case {{ $idx }}:
+		{{- if eq .GenType "direct" }}
+		{{ printf "if obj.%s, err = %sE(tmp%s); err != nil {" .FieldName .AssignExpr .AssignArgs }}
+			{{ printf "return &m2s.KeyError{Key: %q, Err: err}" .JsonName }}
+		}
+		{{- else }}
+		{{ if .TypeConv }}num{{ else }}val{{ end }}{{ printf ", err := %sE(tmp%s)" .AssignExpr .AssignArgs }}
+		if err != nil {
+			{{ printf "return &m2s.KeyError{Key: %q, Err: err}" .JsonName }}
+		}
+			{{- if .TypeConv }}
+		{{ printf "val := (%s)(num)" .TypeConv }}
+			{{- end }}
+			{{- if .IsPointer }}
+		{{ printf "obj.%s = &val" .FieldName }}
+			{{- else }}
+		{{ printf "obj.%s = val" .FieldName }}
+			{{- end }}
+		{{- end }}
+	{{- end }}
+	}
+	{{- if .OtherFields }}
+
+	// 需要手动处理的字段
+	{{- range .OtherFields }}
+	{{ printf "// obj.%s = ? (%s)" .FieldName .JsonName }}
+	{{- end }}
+	{{- end }}
+	return nil
+}
+{{- end }}
+{{- if .RowsName }}
+
+// {{.RowsName}} 按列名扫描所有行后关闭 rows，列与字段的对应关系只计算一次；未知的列返回 *m2s.UnknownKeyError
+func {{.RowsName}}(rows *sql.Rows) (list []*{{$model}}, err error) {
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	fields := make([]int, len(columns))
+	var unknown []string
+	for idx, column := range columns {
+		if fields[idx] = {{.IndexName}}(column); fields[idx] >= 0 {
+			continue
+		}
+		{{- if .OtherFields }}
+		switch column {
+		case {{ range $idx, $fd := .OtherFields }}{{ if $idx }}, {{ end }}{{ printf "%q" .JsonName }}{{ end }}:
+			return nil, &m2s.KeyError{Key: column, Err: m2s.ErrUnsupportedField}
+		}
+		{{- end }}
+		unknown = append(unknown, column)
+	}
+	if len(unknown) > 0 {
+		return nil, &m2s.UnknownKeyError{Keys: unknown}
+	}
+
+	vals := make([]interface{}, len(columns))
+	ptrs := make([]interface{}, len(columns))
+	for idx := range vals {
+		ptrs[idx] = &vals[idx]
+	}
+	for rows.Next() {
+		if err = rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+		obj := {{.NewExpr}}
+		{{- if .InitMethod }}
+		obj.{{.InitMethod}}()
+		{{- end }}
+		for idx, val := range vals {
+			if err = {{.SetName}}(obj, fields[idx], val); err != nil {
+				return nil, err
+			}
+		}
+		list = append(list, obj)
+	}
+	return list, rows.Err()
+}
+{{- end }}
+{{- if .RowName }}
+
+// {{.RowName}} 扫描单行；*sql.Row 无法获取列名，SELECT 的列必须与 {{.ColumnsName}} 的顺序一致
+func {{.RowName}}(row *sql.Row) (obj *{{$model}}, err error) {
+	vals := make([]interface{}, len({{.ColumnsName}}))
+	ptrs := make([]interface{}, len(vals))
+	for idx := range vals {
+		ptrs[idx] = &vals[idx]
+	}
+	if err = row.Scan(ptrs...); err != nil {
+		return nil, err
+	}
+	obj = {{.NewExpr}}
+	{{- if .InitMethod }}
+	obj.{{.InitMethod}}()
+	{{- end }}
+	for idx, val := range vals {
+		if err = {{.SetName}}(obj, idx, val); err != nil {
+			return nil, err
+		}
+	}
+	return obj, nil
+}
+{{- end }}
+`
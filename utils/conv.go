@@ -62,6 +62,19 @@ func IsBaseType(typ string) bool {
 	return false
 }
 
+func IsNumberType(typ string) bool {
+	return IsBaseType(typ) && typ != "bool" && typ != "string"
+}
+
+func InStrings(list []string, str string) bool {
+	for _, s := range list {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
+
 func JsonPretty(obj interface{}) string {
 	ds, _ := json.MarshalIndent(obj, "", "  ")
 	return string(ds)
@@ -0,0 +1,145 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffOp 行的编辑操作: ' ' 相同, '-' 删除, '+' 新增；a、b 为操作前两边已处理的行数
+type diffOp struct {
+	kind byte
+	line string
+	a, b int
+}
+
+// UnifiedDiff 输出 old 到 new 的 unified diff（上下文 3 行），相同时返回空
+func UnifiedDiff(name string, old, new []byte) string {
+	ops := diffLines(splitLines(string(old)), splitLines(string(new)))
+
+	const ctx = 3
+	var sb strings.Builder
+	for i := 0; i < len(ops); {
+		for i < len(ops) && ops[i].kind == ' ' {
+			i++
+		}
+		if i == len(ops) {
+			break
+		}
+		if sb.Len() == 0 {
+			fmt.Fprintf(&sb, "--- a/%s\n+++ b/%s\n", name, name)
+		}
+
+		// 修改之间相同的行不超过 2*ctx 时两边的上下文相连，合并为一个 hunk
+		start, end := i-ctx, i+1
+		if start < 0 {
+			start = 0
+		}
+		for j := i + 1; j < len(ops) && j-end <= 2*ctx; j++ {
+			if ops[j].kind != ' ' {
+				end = j + 1
+			}
+		}
+		stop := end + ctx
+		if stop > len(ops) {
+			stop = len(ops)
+		}
+
+		na, nb := 0, 0
+		for _, op := range ops[start:stop] {
+			if op.kind != '+' {
+				na++
+			}
+			if op.kind != '-' {
+				nb++
+			}
+		}
+		la, lb := ops[start].a, ops[start].b
+		if na > 0 {
+			la++
+		}
+		if nb > 0 {
+			lb++
+		}
+		fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", la, na, lb, nb)
+		for _, op := range ops[start:stop] {
+			sb.WriteByte(op.kind)
+			sb.WriteString(op.line)
+			if !strings.HasSuffix(op.line, "\n") {
+				sb.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		i = stop
+	}
+	return sb.String()
+}
+
+// splitLines 按行分割，每行保留换行符，最后一行没有换行符时与有换行符的行不相同
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines Myers 算法，返回把 a 编辑为 b 的最短操作序列
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	max := n + m
+	off := max + 1
+	v := make([]int, 2*max+3)
+
+	// trace[d] 为第 d 步结束后对角线 -d..d 上的 x（trace[d][k+d]），只保存用到的 2d+1 条对角线用于回溯
+	var trace [][]int
+	for d, found := 0, false; d <= max && !found; d++ {
+		for k := -d; k <= d; k += 2 {
+			x := v[k-1+off] + 1
+			if k == -d || (k != d && v[k-1+off] < v[k+1+off]) {
+				x = v[k+1+off]
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[k+off] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+		trace = append(trace, append([]int(nil), v[off-d:off+d+1]...))
+	}
+
+	var ops []diffOp
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		prev := trace[d-1]
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && prev[k-1+d-1] < prev[k+1+d-1]) {
+			prevK = k + 1
+		}
+		prevX := prev[prevK+d-1]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x, y = x-1, y-1
+			ops = append(ops, diffOp{kind: ' ', line: a[x], a: x, b: y})
+		}
+		if x == prevX {
+			y--
+			ops = append(ops, diffOp{kind: '+', line: b[y], a: x, b: y})
+		} else {
+			x--
+			ops = append(ops, diffOp{kind: '-', line: a[x], a: x, b: y})
+		}
+	}
+	for x > 0 && y > 0 {
+		x, y = x-1, y-1
+		ops = append(ops, diffOp{kind: ' ', line: a[x], a: x, b: y})
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
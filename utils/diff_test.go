@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// numLines 第 from 到 to 行，每行内容为行号
+func numLines(from, to int) string {
+	var sb strings.Builder
+	for i := from; i <= to; i++ {
+		fmt.Fprintf(&sb, "%d\n", i)
+	}
+	return sb.String()
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	const head = "--- a/f.go\n+++ b/f.go\n"
+	cases := []struct {
+		name     string
+		old, new string
+		want     string
+	}{
+		{"identical", "a\nb\n", "a\nb\n", ""},
+		{"both empty", "", "", ""},
+		{"empty old", "", "a\nb\n", head + "@@ -0,0 +1,2 @@\n+a\n+b\n"},
+		{"empty new", "a\nb\n", "", head + "@@ -1,2 +0,0 @@\n-a\n-b\n"},
+		{
+			"replace",
+			numLines(1, 9),
+			strings.Replace(numLines(1, 9), "5\n", "five\n", 1),
+			head + "@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			// 修改之间相同的行为 6 行，两边的上下文相连
+			"merged hunks",
+			numLines(1, 12),
+			strings.Replace(strings.Replace(numLines(1, 12), "2\n", "x\n", 1), "9\n", "y\n", 1),
+			head + "@@ -1,12 +1,12 @@\n 1\n-2\n+x\n 3\n 4\n 5\n 6\n 7\n 8\n-9\n+y\n 10\n 11\n 12\n",
+		},
+		{
+			// 修改之间相同的行为 7 行，分为两个 hunk
+			"split hunks",
+			numLines(1, 14),
+			numLines(1, 1) + numLines(3, 9) + numLines(11, 14),
+			head + "@@ -1,5 +1,4 @@\n 1\n-2\n 3\n 4\n 5\n@@ -7,7 +6,6 @@\n 7\n 8\n 9\n-10\n 11\n 12\n 13\n",
+		},
+		{
+			"missing final newline in old",
+			"a\nb",
+			"a\nb\n",
+			head + "@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+		{
+			"missing final newline in new",
+			"a\nb\n",
+			"a\nb\nc",
+			head + "@@ -1,2 +1,3 @@\n a\n b\n+c\n\\ No newline at end of file\n",
+		},
+	}
+	for _, c := range cases {
+		got := UnifiedDiff("f.go", []byte(c.old), []byte(c.new))
+		if got != c.want {
+			t.Errorf("%s: diff mismatch.\ngot:\n%s\nwant:\n%s", c.name, got, c.want)
+		}
+	}
+}
+
+func TestDiffLinesMinimal(t *testing.T) {
+	a := splitLines(numLines(1, 200))
+	b := splitLines(strings.Replace(numLines(1, 200), "100\n", "", 1) + "201\n")
+	ops := diffLines(a, b)
+	edits := 0
+	var gotA, gotB []string
+	for _, op := range ops {
+		if op.kind != ' ' {
+			edits++
+		}
+		if op.kind != '+' {
+			gotA = append(gotA, op.line)
+		}
+		if op.kind != '-' {
+			gotB = append(gotB, op.line)
+		}
+	}
+	if edits != 2 {
+		t.Errorf("edits=%d want=2", edits)
+	}
+	if strings.Join(gotA, "") != strings.Join(a, "") || strings.Join(gotB, "") != strings.Join(b, "") {
+		t.Errorf("ops do not rebuild input")
+	}
+}
@@ -22,3 +22,10 @@ func ToCap(str string) string {
 	cap := strings.ToUpper(str[:1])
 	return cap + str[1:]
 }
+
+func ToUnCap(str string) string {
+	if str == "" {
+		return ""
+	}
+	return strings.ToLower(str[:1]) + str[1:]
+}